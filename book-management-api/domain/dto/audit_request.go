@@ -0,0 +1,6 @@
+package dto
+
+type AuditRequest struct {
+	From string `query:"from"`
+	To   string `query:"to"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+import (
+	"book-management-api/internal/audit"
+)
+
+type AuditTrailResponse struct {
+	Records    []audit.Record `json:"records"`
+	ChainValid bool           `json:"chain_valid"`
+	ChainError string         `json:"chain_error,omitempty"`
+}
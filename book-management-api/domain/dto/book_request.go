@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type ISBNParam struct {
 	ISBN string `param:"isbn" validate:"required,min=10,max=13,isbn"`
 }
@@ -17,3 +19,28 @@ type UpdateBook struct {
 	ISBN        string `param:"isbn" validate:"required,min=10,max=13,isbn"`
 	ReleaseDate string `json:"release_date" validate:"required"`
 }
+
+type BookFilterRequest struct {
+	Released       string `query:"released"`
+	ReleasedAfter  string `query:"released_after"`
+	ReleasedBefore string `query:"released_before"`
+}
+
+// BookFilter narrows the book list; zero times are not applied
+type BookFilter struct {
+	// ReleasedFrom keeps books released at or after this time
+	ReleasedFrom time.Time
+	// ReleasedUntil keeps books released before this time
+	ReleasedUntil time.Time
+}
+
+// Narrow keeps the later start and the earlier end of the filter and the
+// given bounds; zero bounds are ignored
+func (f *BookFilter) Narrow(from, until time.Time) {
+	if !from.IsZero() && from.After(f.ReleasedFrom) {
+		f.ReleasedFrom = from
+	}
+	if !until.IsZero() && (f.ReleasedUntil.IsZero() || until.Before(f.ReleasedUntil)) {
+		f.ReleasedUntil = until
+	}
+}
@@ -1,12 +1,44 @@
 package dto
 
 import (
-	"time"
+	"book-management-api/domain/entity"
+	"book-management-api/internal/parser"
 )
 
 type BookResponse struct {
-	Title       string    `json:"title"`
-	Author      string    `json:"author"`
-	ISBN        string    `json:"isbn"`
-	ReleaseDate time.Time `json:"release_date"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	ISBN   string `json:"isbn"`
+	// ReleaseDate is written in the format selected by the date_format query
+	// parameter, RFC 3339 by default
+	ReleaseDate parser.FormattedTime `json:"release_date"`
+	// OriginalReleaseDate is the release date in its own calendar, when it
+	// was not given as a Gregorian date
+	OriginalReleaseDate string `json:"original_release_date,omitempty"`
+}
+
+// NewBookResponse builds the response for book, writing its release date in format
+func NewBookResponse(book entity.Book, format parser.DateFormat) BookResponse {
+	return BookResponse{
+		Title:               book.Title,
+		Author:              book.Author,
+		ISBN:                book.ISBN,
+		ReleaseDate:         parser.FormattedTime{Time: book.ReleaseDate, Format: format},
+		OriginalReleaseDate: book.OriginalReleaseDate,
+	}
+}
+
+// NewBookPage builds the responses for a page of books
+func NewBookPage(page PaginatedResponse[entity.Book], format parser.DateFormat) PaginatedResponse[BookResponse] {
+	books := make([]BookResponse, 0, len(page.Data))
+	for _, book := range page.Data {
+		books = append(books, NewBookResponse(book, format))
+	}
+	return PaginatedResponse[BookResponse]{
+		Data:       books,
+		Page:       page.Page,
+		Limit:      page.Limit,
+		Total:      page.Total,
+		TotalPages: page.TotalPages,
+	}
 }
@@ -0,0 +1,5 @@
+package dto
+
+type LogsRequest struct {
+	Since string `query:"since"`
+}
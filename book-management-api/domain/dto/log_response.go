@@ -0,0 +1,14 @@
+package dto
+
+import (
+	"time"
+)
+
+type LogEntryResponse struct {
+	Time      time.Time `json:"time"`
+	Level     string    `json:"level"`
+	Message   string    `json:"message"`
+	RequestID string    `json:"request_id,omitempty"`
+	Method    string    `json:"method,omitempty"`
+	Route     string    `json:"route,omitempty"`
+}
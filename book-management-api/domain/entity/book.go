@@ -11,6 +11,9 @@ type Book struct {
 	Author      string    `json:"author"`
 	ISBN        string    `json:"isbn"`
 	ReleaseDate time.Time `json:"release_date"`
+	// OriginalReleaseDate is the release date as given in a non-Gregorian
+	// calendar, such as "Reiwa 3"; empty for Gregorian dates
+	OriginalReleaseDate string `json:"original_release_date,omitempty"`
 }
 
 // BookStore manages the in-memory storage of books
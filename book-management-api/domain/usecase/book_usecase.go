@@ -3,7 +3,9 @@ package usecase
 import (
 	"book-management-api/domain/dto"
 	"book-management-api/domain/entity"
+	"book-management-api/internal/audit"
 	"book-management-api/internal/logger"
+	"context"
 	"errors"
 	"fmt"
 	"sort"
@@ -11,20 +13,22 @@ import (
 
 type bookUsecase struct {
 	logger logger.Logger
+	audit  audit.Trail
 }
 
 type IBookUsecase interface {
-	GetBooks(pagination dto.PaginationRequest) (dto.PaginatedResponse[entity.Book], error)
-	GetBookByISBN(isbn string) (*entity.Book, error)
-	CreateBook(book entity.Book) (*entity.Book, error)
-	UpdateBook(book entity.Book) (*entity.Book, error)
-	DeleteBookByISBN(isbn string) (*entity.Book, error)
+	GetBooks(ctx context.Context, pagination dto.PaginationRequest, filter dto.BookFilter) (dto.PaginatedResponse[entity.Book], error)
+	GetBookByISBN(ctx context.Context, isbn string) (*entity.Book, error)
+	CreateBook(ctx context.Context, book entity.Book) (*entity.Book, error)
+	UpdateBook(ctx context.Context, book entity.Book) (*entity.Book, error)
+	DeleteBookByISBN(ctx context.Context, isbn string) (*entity.Book, error)
 }
 
 // NewBankService new bank service
-func NewBookUsecase(logger logger.Logger) *bookUsecase {
+func NewBookUsecase(logger logger.Logger, auditTrail audit.Trail) *bookUsecase {
 	return &bookUsecase{
 		logger: logger,
+		audit:  auditTrail,
 	}
 }
 
@@ -33,14 +37,20 @@ var store = &entity.BookStore{
 	Books: make(map[string]entity.Book),
 }
 
-// GetBooks handles retrieving all books with pagination
-func (u *bookUsecase) GetBooks(pagination dto.PaginationRequest) (dto.PaginatedResponse[entity.Book], error) {
+// GetBooks handles retrieving all books matching the filter with pagination
+func (u *bookUsecase) GetBooks(ctx context.Context, pagination dto.PaginationRequest, filter dto.BookFilter) (dto.PaginatedResponse[entity.Book], error) {
 	store.Mutex.RLock()
 	defer store.Mutex.RUnlock()
 
 	// Convert map to slice for pagination
 	var books []entity.Book
 	for _, book := range store.Books {
+		if !filter.ReleasedFrom.IsZero() && book.ReleaseDate.Before(filter.ReleasedFrom) {
+			continue
+		}
+		if !filter.ReleasedUntil.IsZero() && !book.ReleaseDate.Before(filter.ReleasedUntil) {
+			continue
+		}
 		books = append(books, book)
 	}
 
@@ -100,7 +110,7 @@ func PaginateBooks(books []entity.Book, page, limit int) ([]entity.Book, int) {
 }
 
 // GetBookByISBN handles retrieving a single book by ISBN
-func (u *bookUsecase) GetBookByISBN(isbn string) (*entity.Book, error) {
+func (u *bookUsecase) GetBookByISBN(ctx context.Context, isbn string) (*entity.Book, error) {
 	store.Mutex.RLock()
 	defer store.Mutex.RUnlock()
 
@@ -113,7 +123,7 @@ func (u *bookUsecase) GetBookByISBN(isbn string) (*entity.Book, error) {
 }
 
 // CreateBook handles book creation
-func (u *bookUsecase) CreateBook(book entity.Book) (*entity.Book, error) {
+func (u *bookUsecase) CreateBook(ctx context.Context, book entity.Book) (*entity.Book, error) {
 
 	store.Mutex.Lock()
 	defer store.Mutex.Unlock()
@@ -123,37 +133,49 @@ func (u *bookUsecase) CreateBook(book entity.Book) (*entity.Book, error) {
 		return nil, errors.New("Book already exists")
 	}
 
+	// Record the change before applying it so no mutation goes unaudited
+	if err := u.audit.Record(ctx, audit.ActionCreate, nil, &book); err != nil {
+		u.logger.ErrorContext(ctx, err.Error())
+		return nil, errors.New("Failed to record audit trail")
+	}
+
 	// Store the book
 	store.Books[book.ISBN] = book
 
 	// Log asynchronously
-	u.logger.Info(fmt.Sprintf("Book created: %s", book.ISBN))
+	u.logger.InfoContext(ctx, fmt.Sprintf("Book created: %s", book.ISBN))
 
 	return &book, nil
 }
 
 // UpdateBookByISBN handles updating a book
-func (u *bookUsecase) UpdateBook(book entity.Book) (*entity.Book, error) {
+func (u *bookUsecase) UpdateBook(ctx context.Context, book entity.Book) (*entity.Book, error) {
 
 	store.Mutex.Lock()
 	defer store.Mutex.Unlock()
 
 	// Check if book exists
-	if _, exists := store.Books[book.ISBN]; !exists {
+	existing, exists := store.Books[book.ISBN]
+	if !exists {
 		return nil, errors.New("Book not found")
 	}
 
+	if err := u.audit.Record(ctx, audit.ActionUpdate, &existing, &book); err != nil {
+		u.logger.ErrorContext(ctx, err.Error())
+		return nil, errors.New("Failed to record audit trail")
+	}
+
 	// Update the book
 	store.Books[book.ISBN] = book
 
 	// Log asynchronously
-	u.logger.Info(fmt.Sprintf("Book updated: %s", book.ISBN))
+	u.logger.InfoContext(ctx, fmt.Sprintf("Book updated: %s", book.ISBN))
 
 	return &book, nil
 }
 
 // DeleteBookByISBN handles deleting a book
-func (u *bookUsecase) DeleteBookByISBN(isbn string) (*entity.Book, error) {
+func (u *bookUsecase) DeleteBookByISBN(ctx context.Context, isbn string) (*entity.Book, error) {
 	store.Mutex.Lock()
 	defer store.Mutex.Unlock()
 
@@ -165,11 +187,16 @@ func (u *bookUsecase) DeleteBookByISBN(isbn string) (*entity.Book, error) {
 	// Get the book before deleting
 	book := store.Books[isbn]
 
+	if err := u.audit.Record(ctx, audit.ActionDelete, &book, nil); err != nil {
+		u.logger.ErrorContext(ctx, err.Error())
+		return nil, errors.New("Failed to record audit trail")
+	}
+
 	// Delete the book
 	delete(store.Books, isbn)
 
 	// Log asynchronously
-	u.logger.Info(fmt.Sprintf("Book deleted: %s", isbn))
+	u.logger.InfoContext(ctx, fmt.Sprintf("Book deleted: %s", isbn))
 
 	return &book, nil
 }
@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"book-management-api/domain/entity"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Change holds the before and after value of a single book field
+type Change struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Record is one entry of the audit trail. Each record stores the hash of its
+// predecessor so rewriting or removing an entry breaks the chain.
+type Record struct {
+	Sequence  uint64            `json:"seq"`
+	Time      time.Time         `json:"time"`
+	Actor     string            `json:"actor"`
+	Action    Action            `json:"action"`
+	ISBN      string            `json:"isbn"`
+	Before    *entity.Book      `json:"before,omitempty"`
+	After     *entity.Book      `json:"after,omitempty"`
+	Diff      map[string]Change `json:"diff,omitempty"`
+	ClientIP  string            `json:"client_ip"`
+	RequestID string            `json:"request_id"`
+	PrevHash  string            `json:"prev_hash"`
+	Hash      string            `json:"hash"`
+}
+
+// Trail records book mutations and answers queries over them
+type Trail interface {
+	Record(ctx context.Context, action Action, before, after *entity.Book) error
+	Query(from, to time.Time) ([]Record, error)
+	Verify() error
+}
+
+// unsealedSuffix ends the JSON of a record whose hash is not set yet; Hash
+// is the last field of Record
+const unsealedSuffix = `"hash":""}`
+
+// computeHash hashes the stored bytes of a record with an empty hash, chained
+// to the previous hash. Hashing the bytes as written rather than the
+// re-encoded record keeps old records valid when Record or entity.Book gain
+// fields.
+func computeHash(prevHash string, unsealed []byte) string {
+	sum := sha256.Sum256(append([]byte(prevHash), unsealed...))
+	return hex.EncodeToString(sum[:])
+}
+
+// sealRecord encodes record, whose Hash must be empty, and fills in its hash
+func sealRecord(record Record) ([]byte, string, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, "", err
+	}
+	if !bytes.HasSuffix(data, []byte(unsealedSuffix)) {
+		return nil, "", fmt.Errorf("audit record does not end with its hash")
+	}
+	hash := computeHash(record.PrevHash, data)
+	line := append(data[:len(data)-len(`""}`)], `"`+hash+`"}`...)
+	return line, hash, nil
+}
+
+// unsealLine returns a stored line as it was before its hash was filled in
+func unsealLine(line []byte, hash string) ([]byte, bool) {
+	sealed := []byte(`"hash":"` + hash + `"}`)
+	if !bytes.HasSuffix(line, sealed) {
+		return nil, false
+	}
+	unsealed := append([]byte(nil), line[:len(line)-len(sealed)]...)
+	return append(unsealed, unsealedSuffix...), true
+}
+
+// diffBooks lists the fields that differ between before and after
+func diffBooks(before, after *entity.Book) map[string]Change {
+	var b, a entity.Book
+	if before != nil {
+		b = *before
+	}
+	if after != nil {
+		a = *after
+	}
+
+	fields := []struct {
+		name          string
+		before, after string
+	}{
+		{"title", b.Title, a.Title},
+		{"author", b.Author, a.Author},
+		{"isbn", b.ISBN, a.ISBN},
+		{"release_date", formatDate(before, b.ReleaseDate), formatDate(after, a.ReleaseDate)},
+		{"original_release_date", b.OriginalReleaseDate, a.OriginalReleaseDate},
+	}
+
+	diff := make(map[string]Change)
+	for _, field := range fields {
+		if field.before != field.after {
+			diff[field.name] = Change{Before: field.before, After: field.after}
+		}
+	}
+	return diff
+}
+
+func formatDate(book *entity.Book, t time.Time) string {
+	if book == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// PathFromEnv returns AUDIT_LOG_FILE or the default "audit.log"
+func PathFromEnv() string {
+	if path := os.Getenv("AUDIT_LOG_FILE"); path != "" {
+		return path
+	}
+	return "audit.log"
+}
@@ -0,0 +1,206 @@
+package audit
+
+import (
+	"book-management-api/domain/entity"
+	"book-management-api/internal/requestctx"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileTrail is an append-only, hash-chained audit log stored as JSON lines
+type FileTrail struct {
+	path     string
+	file     *os.File
+	lastSeq  uint64
+	lastHash string
+	mutex    sync.Mutex
+}
+
+// storedRecord is a record along with the line it was read from
+type storedRecord struct {
+	Record
+	line []byte
+}
+
+// NewFileTrail opens (or creates) the audit file at path, verifies it and
+// resumes the chain from its last record. A tampered file is refused, as
+// chaining new records onto it would vouch for the tampering.
+func NewFileTrail(path string) (*FileTrail, error) {
+	trail := &FileTrail{path: path}
+
+	records, err := trail.readAll()
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyChain(records); err != nil {
+		return nil, err
+	}
+	if len(records) > 0 {
+		last := records[len(records)-1]
+		trail.lastSeq = last.Sequence
+		trail.lastHash = last.Hash
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file: %w", err)
+	}
+	trail.file = file
+
+	return trail, nil
+}
+
+// Record appends a new entry for the mutation described by before and after,
+// taking the actor, client IP and request ID from ctx
+func (t *FileTrail) Record(ctx context.Context, action Action, before, after *entity.Book) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	record := Record{
+		Sequence: t.lastSeq + 1,
+		Time:     time.Now().UTC(),
+		Actor:    requestctx.AnonymousActor,
+		Action:   action,
+		Before:   before,
+		After:    after,
+		Diff:     diffBooks(before, after),
+		PrevHash: t.lastHash,
+	}
+	if after != nil {
+		record.ISBN = after.ISBN
+	} else if before != nil {
+		record.ISBN = before.ISBN
+	}
+	if info, ok := requestctx.FromContext(ctx); ok {
+		record.Actor = info.Actor
+		record.ClientIP = info.ClientIP
+		record.RequestID = info.ID
+	}
+
+	data, hash, err := sealRecord(record)
+	if err != nil {
+		return err
+	}
+	record.Hash = hash
+
+	info, err := t.file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	if _, err := t.file.Write(append(data, '\n')); err != nil {
+		// Remove a partly written line so the next record chains onto the last whole one
+		if truncateErr := t.file.Truncate(info.Size()); truncateErr != nil {
+			err = fmt.Errorf("%w; removing the partial record also failed: %v", err, truncateErr)
+		}
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+
+	// The line is in the file from here on, so the chain continues from it
+	// even if it cannot be synced
+	t.lastSeq = record.Sequence
+	t.lastHash = record.Hash
+	if err := t.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit record: %w", err)
+	}
+	return nil
+}
+
+// Query returns records whose time falls within [from, to]. A zero bound is
+// treated as open.
+func (t *FileTrail) Query(from, to time.Time) ([]Record, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	records, err := t.readAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []Record{}
+	for _, record := range records {
+		if !from.IsZero() && record.Time.Before(from) {
+			continue
+		}
+		if !to.IsZero() && record.Time.After(to) {
+			continue
+		}
+		result = append(result, record.Record)
+	}
+	return result, nil
+}
+
+// Verify walks the whole file and reports the first record whose hash or
+// link to its predecessor does not match
+func (t *FileTrail) Verify() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	records, err := t.readAll()
+	if err != nil {
+		return err
+	}
+	return verifyChain(records)
+}
+
+// verifyChain checks the sequence, links and hashes of records in file order
+func verifyChain(records []storedRecord) error {
+	prevHash := ""
+	for i, record := range records {
+		if record.Sequence != uint64(i+1) {
+			return fmt.Errorf("audit trail tampered: expected seq %d, found %d", i+1, record.Sequence)
+		}
+		if record.PrevHash != prevHash {
+			return fmt.Errorf("audit trail tampered: seq %d does not link to its predecessor", record.Sequence)
+		}
+		unsealed, ok := unsealLine(record.line, record.Hash)
+		if !ok || computeHash(prevHash, unsealed) != record.Hash {
+			return fmt.Errorf("audit trail tampered: seq %d hash mismatch", record.Sequence)
+		}
+		prevHash = record.Hash
+	}
+	return nil
+}
+
+// Close closes the underlying file
+func (t *FileTrail) Close() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.file.Close()
+}
+
+func (t *FileTrail) readAll() ([]storedRecord, error) {
+	file, err := os.Open(t.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit file: %w", err)
+	}
+	defer file.Close()
+
+	var records []storedRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("audit trail tampered: line %d is not a valid record: %w", line, err)
+		}
+		raw := append([]byte(nil), scanner.Bytes()...)
+		records = append(records, storedRecord{Record: record, line: raw})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit file: %w", err)
+	}
+	return records, nil
+}
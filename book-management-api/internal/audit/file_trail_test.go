@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"book-management-api/domain/entity"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTrail records a create, an update and a delete in a new file and
+// returns its lines
+func writeTrail(t *testing.T, path string) [][]byte {
+	t.Helper()
+	trail, err := NewFileTrail(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	book := entity.Book{Title: "Dune", Author: "Frank Herbert", ISBN: "0441013597", ReleaseDate: time.Date(1965, 8, 1, 0, 0, 0, 0, time.UTC)}
+	updated := book
+	updated.Title = "Dune Messiah"
+
+	ctx := context.Background()
+	for _, step := range []struct {
+		action        Action
+		before, after *entity.Book
+	}{
+		{ActionCreate, nil, &book},
+		{ActionUpdate, &book, &updated},
+		{ActionDelete, &updated, nil},
+	} {
+		if err := trail.Record(ctx, step.action, step.before, step.after); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := trail.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.SplitAfter(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+}
+
+func TestFileTrailReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	writeTrail(t, path)
+
+	trail, err := NewFileTrail(path)
+	if err != nil {
+		t.Fatalf("reopening an intact trail: %v", err)
+	}
+	defer trail.Close()
+	if err := trail.Record(context.Background(), ActionCreate, nil, &entity.Book{ISBN: "0306406152"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := trail.Verify(); err != nil {
+		t.Errorf("verify after reopening: %v", err)
+	}
+
+	records, err := trail.Query(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 || records[3].Sequence != 4 || records[3].PrevHash != records[2].Hash {
+		t.Errorf("the chain did not resume after the last record: %+v", records)
+	}
+}
+
+func TestFileTrailTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(lines [][]byte) [][]byte
+	}{
+		{"edited field", func(lines [][]byte) [][]byte {
+			lines[1] = bytes.Replace(lines[1], []byte("Dune Messiah"), []byte("Dune Mishap"), 1)
+			return lines
+		}},
+		{"deleted line", func(lines [][]byte) [][]byte {
+			return append(lines[:1], lines[2:]...)
+		}},
+		{"reordered lines", func(lines [][]byte) [][]byte {
+			lines[0], lines[1] = lines[1], lines[0]
+			return lines
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.log")
+			lines := tt.tamper(writeTrail(t, path))
+			if err := os.WriteFile(path, bytes.Join(lines, nil), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			if trail, err := NewFileTrail(path); err == nil {
+				trail.Close()
+				t.Fatal("opened a tampered trail")
+			}
+		})
+	}
+}
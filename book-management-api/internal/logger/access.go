@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AccessEntry is the access-log schema shared by the Echo and net/http servers.
+// The request ID, method and route are attached from the request context.
+type AccessEntry struct {
+	RemoteIP  string
+	URI       string
+	Status    int
+	Latency   time.Duration
+	BytesIn   int64
+	BytesOut  int64
+	UserAgent string
+	Error     string
+}
+
+// String renders the entry as "access key=value ..." pairs
+func (a AccessEntry) String() string {
+	line := fmt.Sprintf("access remote_ip=%s uri=%q status=%d latency=%s bytes_in=%d bytes_out=%d user_agent=%q",
+		a.RemoteIP, a.URI, a.Status, a.Latency, a.BytesIn, a.BytesOut, a.UserAgent)
+	if a.Error != "" {
+		line += fmt.Sprintf(" error=%q", a.Error)
+	}
+	return line
+}
+
+// LogAccess writes an access entry, at error level for server errors
+func LogAccess(ctx context.Context, log Logger, entry AccessEntry) {
+	if entry.Status >= 500 {
+		log.ErrorContext(ctx, entry.String())
+		return
+	}
+	log.InfoContext(ctx, entry.String())
+}
@@ -1,6 +1,9 @@
 package logger
 
 import (
+	"book-management-api/internal/requestctx"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
@@ -16,21 +19,57 @@ const (
 )
 
 type AsyncLogger struct {
-	channel chan string
+	channel chan Entry
 	once    sync.Once
-	file    *os.File
+	done    chan struct{}
+	sinks   []Sink
+	sampler *sampler
 	mutex   sync.Mutex
 }
 
-func NewAsyncLogger() *AsyncLogger {
-	file, err := os.OpenFile("app.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		panic(fmt.Sprintf("Failed to open log file: %v", err))
+// Option configures an AsyncLogger
+type Option func(*AsyncLogger)
+
+// WithSink adds a sink the logger fans out to
+func WithSink(sink Sink) Option {
+	return func(l *AsyncLogger) {
+		l.sinks = append(l.sinks, sink)
+	}
+}
+
+// WithSampling enables sampling of repetitive messages at the given level
+func WithSampling(level LogLevel, config SamplingConfig) Option {
+	return func(l *AsyncLogger) {
+		l.sampler.configs[level] = config
 	}
+}
+
+// WithSampleKey overrides how messages are grouped for sampling
+func WithSampleKey(keyFunc func(Entry) string) Option {
+	return func(l *AsyncLogger) {
+		l.sampler.keyFunc = keyFunc
+	}
+}
 
+// NewAsyncLogger creates a logger writing to the given sinks. Without any
+// sink it appends text lines to app.log in the working directory.
+func NewAsyncLogger(opts ...Option) *AsyncLogger {
 	logger := &AsyncLogger{
-		channel: make(chan string, 100),
-		file:    file,
+		channel: make(chan Entry, 100),
+		done:    make(chan struct{}),
+		sampler: newSampler(),
+	}
+
+	for _, opt := range opts {
+		opt(logger)
+	}
+
+	if len(logger.sinks) == 0 {
+		sink, err := NewFileSink("app.log", DebugLevel, TextFormatter)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to open log file: %v", err))
+		}
+		logger.sinks = append(logger.sinks, sink)
 	}
 
 	logger.once.Do(func() {
@@ -40,45 +79,114 @@ func NewAsyncLogger() *AsyncLogger {
 	return logger
 }
 
+// Close drains pending entries and closes every sink
 func (l *AsyncLogger) Close() error {
 	close(l.channel)
-	return l.file.Close()
+	<-l.done
+
+	var errs []error
+	for _, sink := range l.sinks {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
 func (l *AsyncLogger) worker() {
-	for msg := range l.channel {
-		l.writeToFile(msg)
+	defer close(l.done)
+
+	// Periodically report suppressed messages whose window has closed
+	var tick <-chan time.Time
+	if l.sampler.enabled() {
+		ticker := time.NewTicker(l.sampler.tickInterval())
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case entry, ok := <-l.channel:
+			if !ok {
+				for _, summary := range l.sampler.flush(time.Now(), true) {
+					l.writeToSinks(summary)
+				}
+				return
+			}
+			l.writeToSinks(entry)
+		case now := <-tick:
+			for _, summary := range l.sampler.flush(now, false) {
+				l.writeToSinks(summary)
+			}
+		}
 	}
 }
 
-func (l *AsyncLogger) writeToFile(msg string) {
+func (l *AsyncLogger) writeToSinks(entry Entry) {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	timestamp := time.Now().Format("2006/01/02 15:04:05")
-	fmt.Fprintf(l.file, "%s [LOG] %s\n", timestamp, msg)
-	l.file.Sync()
+	for _, sink := range l.sinks {
+		if !sink.Enabled(entry.Level) {
+			continue
+		}
+		if err := sink.Write(entry); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: sink write failed: %v\n", err)
+		}
+	}
 }
 
-func (l *AsyncLogger) log(level LogLevel, msg string) {
-	logMsg := fmt.Sprintf("%s: %s", level, msg)
+func (l *AsyncLogger) log(ctx context.Context, level LogLevel, msg string) {
+	entry := Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Message: msg,
+	}
+	if info, ok := requestctx.FromContext(ctx); ok {
+		entry.RequestID = info.ID
+		entry.Method = info.Method
+		entry.Route = info.Route
+	}
+
+	keep, summary := l.sampler.allow(entry)
+	if summary != nil {
+		l.enqueue(*summary)
+	}
+	if keep {
+		l.enqueue(entry)
+	}
+}
+
+func (l *AsyncLogger) enqueue(entry Entry) {
 	select {
-	case l.channel <- logMsg:
+	case l.channel <- entry:
 		// Message sent successfully
 	default:
-		// Channel is full, write directly to file
-		l.writeToFile(logMsg)
+		// Channel is full, write directly to the sinks
+		l.writeToSinks(entry)
 	}
 }
 
 func (l *AsyncLogger) Info(msg string) {
-	l.log(InfoLevel, msg)
+	l.log(context.Background(), InfoLevel, msg)
 }
 
 func (l *AsyncLogger) Error(msg string) {
-	l.log(ErrorLevel, msg)
+	l.log(context.Background(), ErrorLevel, msg)
 }
 
 func (l *AsyncLogger) Debug(msg string) {
-	l.log(DebugLevel, msg)
+	l.log(context.Background(), DebugLevel, msg)
+}
+
+func (l *AsyncLogger) InfoContext(ctx context.Context, msg string) {
+	l.log(ctx, InfoLevel, msg)
+}
+
+func (l *AsyncLogger) ErrorContext(ctx context.Context, msg string) {
+	l.log(ctx, ErrorLevel, msg)
+}
+
+func (l *AsyncLogger) DebugContext(ctx context.Context, msg string) {
+	l.log(ctx, DebugLevel, msg)
 }
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"os"
+	"strconv"
+)
+
+// Config describes the sinks a server logger fans out to
+type Config struct {
+	FilePath   string // empty disables the file sink
+	FileLevel  string
+	FileFormat string
+
+	Stream       string // "stdout", "stderr" or empty to disable
+	StreamLevel  string
+	StreamFormat string
+
+	SyslogNetwork string // empty disables the syslog sink
+	SyslogAddress string
+	SyslogLevel   string
+	SyslogFormat  string
+
+	RingSize  int
+	RingLevel string
+
+	// Sampling maps a level to an "interval:first:thereafter" spec
+	Sampling map[LogLevel]string
+}
+
+// ConfigFromEnv reads the LOG_* environment variables, see .env.example
+func ConfigFromEnv() Config {
+	ringSize, err := strconv.Atoi(os.Getenv("LOG_RING_SIZE"))
+	if err != nil {
+		ringSize = 1000
+	}
+
+	return Config{
+		FilePath:      getEnv("LOG_FILE", "app.log"),
+		FileLevel:     getEnv("LOG_FILE_LEVEL", "DEBUG"),
+		FileFormat:    getEnv("LOG_FILE_FORMAT", "text"),
+		Stream:        os.Getenv("LOG_STREAM"),
+		StreamLevel:   getEnv("LOG_STREAM_LEVEL", "INFO"),
+		StreamFormat:  getEnv("LOG_STREAM_FORMAT", "json"),
+		SyslogNetwork: os.Getenv("LOG_SYSLOG_NETWORK"),
+		SyslogAddress: getEnv("LOG_SYSLOG_ADDRESS", DefaultSyslogSocket),
+		SyslogLevel:   getEnv("LOG_SYSLOG_LEVEL", "INFO"),
+		SyslogFormat:  getEnv("LOG_SYSLOG_FORMAT", "message"),
+		RingSize:      ringSize,
+		RingLevel:     getEnv("LOG_RING_LEVEL", "DEBUG"),
+		Sampling:      samplingFromEnv(),
+	}
+}
+
+// samplingFromEnv reads LOG_SAMPLING_INFO, LOG_SAMPLING_ERROR and LOG_SAMPLING_DEBUG
+func samplingFromEnv() map[LogLevel]string {
+	sampling := make(map[LogLevel]string)
+	for _, level := range []LogLevel{DebugLevel, InfoLevel, ErrorLevel} {
+		if spec := os.Getenv("LOG_SAMPLING_" + string(level)); spec != "" {
+			sampling[level] = spec
+		}
+	}
+	return sampling
+}
+
+// Build creates the logger along with the ring buffer backing GET /admin/logs.
+// When a sink or sampling spec fails, the sinks opened so far are closed.
+func (c Config) Build() (logger *AsyncLogger, ring *RingBufferSink, err error) {
+	var sinks []Sink
+	defer func() {
+		if err != nil {
+			for _, sink := range sinks {
+				sink.Close()
+			}
+		}
+	}()
+
+	if c.FilePath != "" {
+		level, format, err := parseLevelAndFormat(c.FileLevel, c.FileFormat)
+		if err != nil {
+			return nil, nil, err
+		}
+		sink, err := NewFileSink(c.FilePath, level, format)
+		if err != nil {
+			return nil, nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	if c.Stream != "" {
+		level, format, err := parseLevelAndFormat(c.StreamLevel, c.StreamFormat)
+		if err != nil {
+			return nil, nil, err
+		}
+		if c.Stream == "stderr" {
+			sinks = append(sinks, NewStderrSink(level, format))
+		} else {
+			sinks = append(sinks, NewStdoutSink(level, format))
+		}
+	}
+
+	if c.SyslogNetwork != "" {
+		level, format, err := parseLevelAndFormat(c.SyslogLevel, c.SyslogFormat)
+		if err != nil {
+			return nil, nil, err
+		}
+		sink, err := NewSyslogSink(c.SyslogNetwork, c.SyslogAddress, level, format)
+		if err != nil {
+			return nil, nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	ringLevel, err := ParseLevel(c.RingLevel)
+	if err != nil {
+		return nil, nil, err
+	}
+	ring = NewRingBufferSink(c.RingSize, ringLevel)
+	sinks = append(sinks, ring)
+
+	var opts []Option
+	for _, sink := range sinks {
+		opts = append(opts, WithSink(sink))
+	}
+	for level, spec := range c.Sampling {
+		config, err := ParseSamplingConfig(spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, WithSampling(level, config))
+	}
+
+	return NewAsyncLogger(opts...), ring, nil
+}
+
+func parseLevelAndFormat(levelName, formatName string) (LogLevel, Formatter, error) {
+	level, err := ParseLevel(levelName)
+	if err != nil {
+		return "", nil, err
+	}
+	format, err := FormatterByName(formatName)
+	if err != nil {
+		return "", nil, err
+	}
+	return level, format, nil
+}
+
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
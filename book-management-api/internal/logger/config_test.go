@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildClosesSinksOnError(t *testing.T) {
+	if _, err := os.ReadDir("/proc/self/fd"); err != nil {
+		t.Skip("open descriptors cannot be counted here")
+	}
+	openFiles := func() int {
+		entries, err := os.ReadDir("/proc/self/fd")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(entries)
+	}
+
+	dir := t.TempDir()
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{
+			name: "syslog unreachable",
+			config: Config{
+				SyslogNetwork: "unixgram",
+				SyslogAddress: filepath.Join(dir, "missing.sock"),
+				SyslogLevel:   "INFO",
+				SyslogFormat:  "message",
+			},
+		},
+		{
+			name:   "ring level invalid",
+			config: Config{RingLevel: "LOUD"},
+		},
+		{
+			name: "sampling spec invalid",
+			config: Config{
+				RingLevel: "DEBUG",
+				Sampling:  map[LogLevel]string{InfoLevel: "1s:100"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.FilePath = filepath.Join(dir, "app.log")
+			tt.config.FileLevel = "DEBUG"
+			tt.config.FileFormat = "text"
+
+			before := openFiles()
+			if _, _, err := tt.config.Build(); err == nil {
+				t.Fatal("Build succeeded, want an error")
+			}
+			if after := openFiles(); after != before {
+				t.Errorf("%d descriptors open after the failed Build, want %d", after, before)
+			}
+		})
+	}
+}
@@ -1,7 +1,16 @@
 package logger
 
+import (
+	"context"
+)
+
 type Logger interface {
 	Info(msg string)
 	Error(msg string)
 	Debug(msg string)
+
+	// Context variants attach the request ID, method and route found in ctx
+	InfoContext(ctx context.Context, msg string)
+	ErrorContext(ctx context.Context, msg string)
+	DebugContext(ctx context.Context, msg string)
 }
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// RingBufferSink keeps the most recent entries in memory for quick debugging
+type RingBufferSink struct {
+	levelFilter
+	entries []Entry
+	next    int
+	full    bool
+	mutex   sync.RWMutex
+}
+
+// NewRingBufferSink creates a buffer holding at most capacity entries
+func NewRingBufferSink(capacity int, level LogLevel) *RingBufferSink {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &RingBufferSink{
+		levelFilter: levelFilter{level: level},
+		entries:     make([]Entry, capacity),
+	}
+}
+
+func (s *RingBufferSink) Write(entry Entry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.entries[s.next] = entry
+	s.next = (s.next + 1) % len(s.entries)
+	if s.next == 0 {
+		s.full = true
+	}
+	return nil
+}
+
+// Since returns buffered entries logged at or after t, oldest first
+func (s *RingBufferSink) Since(t time.Time) []Entry {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var ordered []Entry
+	if s.full {
+		ordered = append(ordered, s.entries[s.next:]...)
+	}
+	ordered = append(ordered, s.entries[:s.next]...)
+
+	result := []Entry{}
+	for _, entry := range ordered {
+		if !entry.Time.Before(t) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+func (s *RingBufferSink) Close() error {
+	return nil
+}
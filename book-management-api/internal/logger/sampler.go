@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SamplingConfig limits repetitive messages: within each Interval the first
+// First messages sharing a key are logged, then only every Thereafter-th one.
+// A Thereafter of 0 drops everything past First.
+type SamplingConfig struct {
+	Interval   time.Duration
+	First      int
+	Thereafter int
+}
+
+// ParseSamplingConfig parses "interval:first:thereafter", e.g. "1s:100:100"
+func ParseSamplingConfig(spec string) (SamplingConfig, error) {
+	parts := strings.Split(strings.TrimSpace(spec), ":")
+	if len(parts) != 3 {
+		return SamplingConfig{}, fmt.Errorf("invalid sampling config %q, expected interval:first:thereafter", spec)
+	}
+
+	interval, err := time.ParseDuration(parts[0])
+	if err != nil || interval <= 0 {
+		return SamplingConfig{}, fmt.Errorf("invalid sampling interval %q", parts[0])
+	}
+	first, err := strconv.Atoi(parts[1])
+	if err != nil || first < 0 {
+		return SamplingConfig{}, fmt.Errorf("invalid sampling first count %q", parts[1])
+	}
+	thereafter, err := strconv.Atoi(parts[2])
+	if err != nil || thereafter < 0 {
+		return SamplingConfig{}, fmt.Errorf("invalid sampling thereafter count %q", parts[2])
+	}
+
+	return SamplingConfig{Interval: interval, First: first, Thereafter: thereafter}, nil
+}
+
+// DefaultSampleKey groups messages by the text before the first ": ", so
+// "Book created: 978..." lines share the key "Book created"
+func DefaultSampleKey(entry Entry) string {
+	if prefix, _, found := strings.Cut(entry.Message, ": "); found {
+		return prefix
+	}
+	return entry.Message
+}
+
+type sampleCounter struct {
+	level       LogLevel
+	key         string
+	windowStart time.Time
+	seen        int
+	suppressed  int
+}
+
+// sampler tracks per-key counts for the levels that have sampling enabled
+type sampler struct {
+	configs  map[LogLevel]SamplingConfig
+	keyFunc  func(Entry) string
+	counters map[string]*sampleCounter
+	mutex    sync.Mutex
+}
+
+func newSampler() *sampler {
+	return &sampler{
+		configs:  make(map[LogLevel]SamplingConfig),
+		keyFunc:  DefaultSampleKey,
+		counters: make(map[string]*sampleCounter),
+	}
+}
+
+func (s *sampler) enabled() bool {
+	return len(s.configs) > 0
+}
+
+// tickInterval is the shortest configured interval, used to flush summaries
+func (s *sampler) tickInterval() time.Duration {
+	var shortest time.Duration
+	for _, config := range s.configs {
+		if shortest == 0 || config.Interval < shortest {
+			shortest = config.Interval
+		}
+	}
+	return shortest
+}
+
+// allow reports whether entry should be logged. When the entry starts a new
+// window for its key, the summary of the previous window is returned too.
+func (s *sampler) allow(entry Entry) (bool, *Entry) {
+	config, ok := s.configs[entry.Level]
+	if !ok {
+		return true, nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := s.keyFunc(entry)
+	counterKey := string(entry.Level) + "|" + key
+	counter, ok := s.counters[counterKey]
+	if !ok {
+		counter = &sampleCounter{level: entry.Level, key: key, windowStart: entry.Time}
+		s.counters[counterKey] = counter
+	}
+
+	var summary *Entry
+	if entry.Time.Sub(counter.windowStart) >= config.Interval {
+		summary = counter.summary(entry.Time)
+		counter.windowStart = entry.Time
+		counter.seen = 0
+		counter.suppressed = 0
+	}
+
+	counter.seen++
+	if counter.seen <= config.First {
+		return true, summary
+	}
+	if config.Thereafter > 0 && (counter.seen-config.First)%config.Thereafter == 0 {
+		return true, summary
+	}
+
+	counter.suppressed++
+	return false, summary
+}
+
+// flush returns summaries for windows that have elapsed by now (or for every
+// window when force is set) and forgets idle keys
+func (s *sampler) flush(now time.Time, force bool) []Entry {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var summaries []Entry
+	for counterKey, counter := range s.counters {
+		if !force && now.Sub(counter.windowStart) < s.configs[counter.level].Interval {
+			continue
+		}
+		if summary := counter.summary(now); summary != nil {
+			summaries = append(summaries, *summary)
+		}
+		delete(s.counters, counterKey)
+	}
+	return summaries
+}
+
+func (c *sampleCounter) summary(now time.Time) *Entry {
+	if c.suppressed == 0 {
+		return nil
+	}
+	return &Entry{
+		Time:    now,
+		Level:   c.level,
+		Message: fmt.Sprintf("sampling: suppressed %d %q messages since %s", c.suppressed, c.key, c.windowStart.Format(time.RFC3339)),
+	}
+}
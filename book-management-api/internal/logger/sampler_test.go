@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplerAllow(t *testing.T) {
+	start := time.Date(2021, 3, 2, 15, 4, 5, 0, time.UTC)
+	since := start.Format(time.RFC3339)
+	at := func(offset time.Duration, level LogLevel, message string) Entry {
+		return Entry{Time: start.Add(offset), Level: level, Message: message}
+	}
+	burst := func(n int, level LogLevel, message string) []Entry {
+		entries := make([]Entry, n)
+		for i := range entries {
+			entries[i] = at(time.Duration(i)*time.Millisecond, level, message)
+		}
+		return entries
+	}
+
+	tests := []struct {
+		name    string
+		config  SamplingConfig
+		entries []Entry
+		// kept lists the indexes of the entries logged, summaries the
+		// summary returned alongside an entry, by index
+		kept      []int
+		summaries map[int]string
+		// flushed holds the summaries left for Close to report
+		flushed []string
+	}{
+		{
+			name:    "first then every third",
+			config:  SamplingConfig{Interval: time.Second, First: 2, Thereafter: 3},
+			entries: burst(10, InfoLevel, "Book created: 0441013597"),
+			kept:    []int{0, 1, 4, 7},
+			flushed: []string{fmt.Sprintf("sampling: suppressed 6 %q messages since %s", "Book created", since)},
+		},
+		{
+			name:    "thereafter zero drops the rest",
+			config:  SamplingConfig{Interval: time.Second, First: 1, Thereafter: 0},
+			entries: burst(4, InfoLevel, "Book created: 0441013597"),
+			kept:    []int{0},
+			flushed: []string{fmt.Sprintf("sampling: suppressed 3 %q messages since %s", "Book created", since)},
+		},
+		{
+			name:   "window rollover",
+			config: SamplingConfig{Interval: time.Second, First: 1, Thereafter: 0},
+			entries: append(burst(4, InfoLevel, "Book created: 0441013597"),
+				at(time.Second, InfoLevel, "Book created: 0306406152"),
+				at(time.Second+time.Millisecond, InfoLevel, "Book created: 0306406152"),
+			),
+			kept: []int{0, 4},
+			summaries: map[int]string{
+				4: fmt.Sprintf("sampling: suppressed 3 %q messages since %s", "Book created", since),
+			},
+			flushed: []string{fmt.Sprintf("sampling: suppressed 1 %q messages since %s", "Book created", start.Add(time.Second).Format(time.RFC3339))},
+		},
+		{
+			name:    "nothing suppressed before rollover",
+			config:  SamplingConfig{Interval: time.Second, First: 2, Thereafter: 0},
+			entries: []Entry{at(0, InfoLevel, "Book created: 1"), at(2*time.Second, InfoLevel, "Book created: 2")},
+			kept:    []int{0, 1},
+		},
+		{
+			name:   "keys are counted apart",
+			config: SamplingConfig{Interval: time.Second, First: 1, Thereafter: 0},
+			entries: []Entry{
+				at(0, InfoLevel, "Book created: 1"),
+				at(1, InfoLevel, "Book deleted: 1"),
+				at(2, InfoLevel, "Book created: 2"),
+				at(3, InfoLevel, "Book deleted: 2"),
+			},
+			kept: []int{0, 1},
+			flushed: []string{
+				fmt.Sprintf("sampling: suppressed 1 %q messages since %s", "Book created", since),
+				fmt.Sprintf("sampling: suppressed 1 %q messages since %s", "Book deleted", since),
+			},
+		},
+		{
+			name:    "other levels are not sampled",
+			config:  SamplingConfig{Interval: time.Second, First: 1, Thereafter: 0},
+			entries: burst(3, ErrorLevel, "Book created: 0441013597"),
+			kept:    []int{0, 1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSampler()
+			s.configs[InfoLevel] = tt.config
+
+			var kept []int
+			summaries := map[int]string{}
+			for i, entry := range tt.entries {
+				keep, summary := s.allow(entry)
+				if keep {
+					kept = append(kept, i)
+				}
+				if summary != nil {
+					if !summary.Time.Equal(entry.Time) || summary.Level != entry.Level {
+						t.Errorf("summary at %d = %+v, want time %s and level %s", i, *summary, entry.Time, entry.Level)
+					}
+					summaries[i] = summary.Message
+				}
+			}
+			if !reflect.DeepEqual(kept, tt.kept) {
+				t.Errorf("kept %v, want %v", kept, tt.kept)
+			}
+			if tt.summaries == nil {
+				tt.summaries = map[int]string{}
+			}
+			if !reflect.DeepEqual(summaries, tt.summaries) {
+				t.Errorf("summaries %q, want %q", summaries, tt.summaries)
+			}
+
+			last := tt.entries[len(tt.entries)-1].Time
+			if early := s.flush(last, false); len(early) != 0 {
+				t.Errorf("flush before the window closed returned %v", early)
+			}
+			var flushed []string
+			for _, summary := range s.flush(last, true) {
+				flushed = append(flushed, summary.Message)
+			}
+			sort.Strings(flushed)
+			if !reflect.DeepEqual(flushed, tt.flushed) {
+				t.Errorf("flushed %q, want %q", flushed, tt.flushed)
+			}
+			if len(s.counters) != 0 {
+				t.Errorf("%d counters left after flush", len(s.counters))
+			}
+		})
+	}
+}
+
+func TestSamplerFlushOnClose(t *testing.T) {
+	ring := NewRingBufferSink(10, DebugLevel)
+	logger := NewAsyncLogger(
+		WithSink(ring),
+		WithSampling(InfoLevel, SamplingConfig{Interval: time.Hour, First: 1, Thereafter: 0}),
+	)
+	for _, isbn := range []string{"0441013597", "0306406152", "0441013597"} {
+		logger.Info("Book created: " + isbn)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var messages []string
+	for _, entry := range ring.Since(time.Time{}) {
+		messages = append(messages, entry.Message)
+	}
+	if len(messages) != 2 || messages[0] != "Book created: 0441013597" {
+		t.Fatalf("logged %q, want the first message and a summary", messages)
+	}
+	if want := fmt.Sprintf("sampling: suppressed 2 %q messages since ", "Book created"); !strings.HasPrefix(messages[1], want) {
+		t.Errorf("summary %q, want prefix %q", messages[1], want)
+	}
+}
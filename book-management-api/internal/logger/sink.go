@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Entry is a single log record handed to every sink
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Level     LogLevel  `json:"level"`
+	Message   string    `json:"message"`
+	RequestID string    `json:"request_id,omitempty"`
+	Method    string    `json:"method,omitempty"`
+	Route     string    `json:"route,omitempty"`
+}
+
+// Sink is a destination for log entries. Each sink decides which levels it
+// accepts and how entries are rendered.
+type Sink interface {
+	Enabled(level LogLevel) bool
+	Write(entry Entry) error
+	Close() error
+}
+
+// Formatter renders an entry as a single line (without the trailing newline)
+type Formatter func(entry Entry) string
+
+// TextFormatter renders the classic "2006/01/02 15:04:05 [LOG] INFO: msg" line,
+// followed by the request fields when the entry belongs to a request
+func TextFormatter(entry Entry) string {
+	line := fmt.Sprintf("%s [LOG] %s: %s", entry.Time.Format("2006/01/02 15:04:05"), entry.Level, entry.Message)
+	if entry.RequestID != "" {
+		line += fmt.Sprintf(" request_id=%s method=%s route=%s", entry.RequestID, entry.Method, entry.Route)
+	}
+	return line
+}
+
+// JSONFormatter renders an entry as a single JSON object
+func JSONFormatter(entry Entry) string {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return TextFormatter(entry)
+	}
+	return string(data)
+}
+
+// MessageFormatter renders the message alone, for destinations such as
+// syslog whose framing already carries the time and level
+func MessageFormatter(entry Entry) string {
+	return entry.Message
+}
+
+// FormatterByName resolves "text", "json" or "message" to a formatter
+func FormatterByName(name string) (Formatter, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "text":
+		return TextFormatter, nil
+	case "json":
+		return JSONFormatter, nil
+	case "message":
+		return MessageFormatter, nil
+	default:
+		return nil, fmt.Errorf("unknown log format: %s", name)
+	}
+}
+
+// severity orders levels so sinks can filter on a minimum level
+func (l LogLevel) severity() int {
+	switch l {
+	case DebugLevel:
+		return 0
+	case InfoLevel:
+		return 1
+	case ErrorLevel:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// ParseLevel converts a level name such as "info" to a LogLevel
+func ParseLevel(name string) (LogLevel, error) {
+	switch LogLevel(strings.ToUpper(strings.TrimSpace(name))) {
+	case DebugLevel:
+		return DebugLevel, nil
+	case "", InfoLevel:
+		return InfoLevel, nil
+	case ErrorLevel:
+		return ErrorLevel, nil
+	default:
+		return "", fmt.Errorf("unknown log level: %s", name)
+	}
+}
+
+// levelFilter is embedded by sinks to implement Enabled
+type levelFilter struct {
+	level LogLevel
+}
+
+func (f levelFilter) Enabled(level LogLevel) bool {
+	return level.severity() >= f.level.severity()
+}
@@ -0,0 +1,151 @@
+package logger
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSyslogSocket is the local syslog socket on most Linux systems
+const DefaultSyslogSocket = "/dev/log"
+
+// facilityUser is the RFC 5424 "user-level messages" facility
+const facilityUser = 1
+
+// SyslogSink sends entries to a local syslog daemon over a unix socket using
+// RFC 5424 framing
+type SyslogSink struct {
+	levelFilter
+	network  string
+	address  string
+	appName  string
+	hostname string
+	format   Formatter
+	conn     net.Conn
+	mutex    sync.Mutex
+}
+
+// NewSyslogSink connects to the syslog socket at address. Datagram sockets
+// ("unixgram") carry one message per packet, stream sockets ("unix") use
+// octet-counting framing (RFC 6587).
+func NewSyslogSink(network, address string, level LogLevel, format Formatter) (*SyslogSink, error) {
+	if network == "" {
+		network = "unixgram"
+	}
+	if address == "" {
+		address = DefaultSyslogSocket
+	}
+	if format == nil {
+		format = MessageFormatter
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	sink := &SyslogSink{
+		levelFilter: levelFilter{level: level},
+		network:     network,
+		address:     address,
+		appName:     filepath.Base(os.Args[0]),
+		hostname:    hostname,
+		format:      format,
+	}
+
+	if err := sink.connect(); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+func (s *SyslogSink) connect() error {
+	conn, err := net.Dial(s.network, s.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to syslog socket %s: %w", s.address, err)
+	}
+	s.conn = conn
+	return nil
+}
+
+func (s *SyslogSink) Write(entry Entry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	msg := s.frame(entry)
+
+	// Retry once with a fresh connection in case the daemon was restarted
+	if s.conn != nil {
+		if _, err := s.conn.Write(msg); err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	if err := s.connect(); err != nil {
+		return err
+	}
+	_, err := s.conn.Write(msg)
+	return err
+}
+
+// frame builds "<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG"
+func (s *SyslogSink) frame(entry Entry) []byte {
+	msg := fmt.Sprintf("<%d>1 %s %s %s %d - %s %s",
+		facilityUser*8+syslogSeverity(entry.Level),
+		entry.Time.Format(time.RFC3339Nano),
+		s.hostname,
+		s.appName,
+		os.Getpid(),
+		structuredData(entry),
+		s.format(entry),
+	)
+
+	if s.network == "unix" {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+	return []byte(msg)
+}
+
+func (s *SyslogSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// structuredData carries the request fields as an RFC 5424 SD-ELEMENT
+func structuredData(entry Entry) string {
+	if entry.RequestID == "" {
+		return "-"
+	}
+	return fmt.Sprintf(`[request@32473 id="%s" method="%s" route="%s"]`,
+		sdEscaper.Replace(entry.RequestID),
+		sdEscaper.Replace(entry.Method),
+		sdEscaper.Replace(entry.Route),
+	)
+}
+
+// sdEscaper escapes the characters RFC 5424 reserves in PARAM-VALUE
+var sdEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// syslogSeverity maps a level to its RFC 5424 severity code
+func syslogSeverity(level LogLevel) int {
+	switch level {
+	case ErrorLevel:
+		return 3
+	case DebugLevel:
+		return 7
+	default:
+		return 6
+	}
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// WriterSink writes formatted entries to a file or stream
+type WriterSink struct {
+	levelFilter
+	writer io.Writer
+	format Formatter
+	mutex  sync.Mutex
+}
+
+// NewWriterSink creates a sink writing to an arbitrary writer
+func NewWriterSink(w io.Writer, level LogLevel, format Formatter) *WriterSink {
+	if format == nil {
+		format = TextFormatter
+	}
+	return &WriterSink{
+		levelFilter: levelFilter{level: level},
+		writer:      w,
+		format:      format,
+	}
+}
+
+// NewFileSink opens (or creates) path in append mode
+func NewFileSink(path string, level LogLevel, format Formatter) (*WriterSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	return NewWriterSink(file, level, format), nil
+}
+
+// NewStdoutSink creates a sink writing to standard output (useful in containers)
+func NewStdoutSink(level LogLevel, format Formatter) *WriterSink {
+	return NewWriterSink(os.Stdout, level, format)
+}
+
+// NewStderrSink creates a sink writing to standard error
+func NewStderrSink(level LogLevel, format Formatter) *WriterSink {
+	return NewWriterSink(os.Stderr, level, format)
+}
+
+func (s *WriterSink) Write(entry Entry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, err := fmt.Fprintln(s.writer, s.format(entry)); err != nil {
+		return err
+	}
+
+	// Flush files to disk so entries survive a crash
+	if file, ok := s.writer.(*os.File); ok && file != os.Stdout && file != os.Stderr {
+		return file.Sync()
+	}
+	return nil
+}
+
+// Close closes the underlying writer unless it is a standard stream
+func (s *WriterSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.writer == os.Stdout || s.writer == os.Stderr {
+		return nil
+	}
+	if closer, ok := s.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,425 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Calendar converts dates written in a non-Gregorian calendar, such as
+// "15 Ramadan 1445 AH" or "Reiwa 3", to the Gregorian period they denote
+type Calendar interface {
+	// Name identifies the calendar, such as "islamic"
+	Name() string
+	// Convert reads value when it is written in this calendar. ok is false
+	// when it is not; err reports a value in this calendar that is not a
+	// valid date.
+	Convert(value string, loc *time.Location) (date CalendarDate, ok bool, err error)
+}
+
+// CalendarDate is a date as written in its own calendar, kept for display
+// alongside the Gregorian period it converts to
+type CalendarDate struct {
+	Calendar string
+	// Era is the era name, such as "Reiwa", or "AH" for Islamic dates
+	Era string
+	// Year, Month and Day are counted in the calendar; Month and Day are 0
+	// when not given
+	Year, Month, Day int
+	// Text is the input as written
+	Text string
+	// Period is the Gregorian period the date covers
+	Period    Interval
+	Precision Precision
+}
+
+// String formats the date in its own calendar
+func (d CalendarDate) String() string {
+	switch d.Calendar {
+	case "japanese":
+		if d.Month == 0 {
+			return fmt.Sprintf("%s %d", d.Era, d.Year)
+		}
+		if d.Day == 0 {
+			return fmt.Sprintf("%s %d-%02d", d.Era, d.Year, d.Month)
+		}
+		return fmt.Sprintf("%s %d-%02d-%02d", d.Era, d.Year, d.Month, d.Day)
+	case "islamic":
+		return strings.TrimSpace(fmt.Sprintf("%s %s %d AH", dayText(d.Day), monthText(islamicMonths, d.Month), d.Year))
+	default:
+		return strings.TrimSpace(fmt.Sprintf("%s %s %d (%s)", dayText(d.Day), monthText(julianMonths, d.Month), d.Year, d.Calendar))
+	}
+}
+
+func dayText(day int) string {
+	if day == 0 {
+		return ""
+	}
+	return strconv.Itoa(day)
+}
+
+func monthText(names []string, month int) string {
+	if month < 1 || month > len(names) {
+		return ""
+	}
+	return names[month-1]
+}
+
+// DefaultCalendars returns the Julian, Islamic civil and Japanese era
+// calendars parsers understand by default
+func DefaultCalendars() []Calendar {
+	return []Calendar{JulianCalendar{}, IslamicCalendar{}, JapaneseCalendar{}}
+}
+
+// unixEpochJDN is the Julian day number of January 1, 1970
+const unixEpochJDN = 2440588
+
+// fromJDN returns the start of the Gregorian day with the given Julian day number
+func fromJDN(jdn int, loc *time.Location) time.Time {
+	return time.Date(1970, 1, 1, 0, 0, 0, 0, loc).AddDate(0, 0, jdn-unixEpochJDN)
+}
+
+// The calendar date patterns match the day, month and year of "2021-05-01",
+// "1 May 2021", "May 1, 2021", "May 2021" and "2021" once era markers are removed
+var (
+	calendarNumeric  = regexp.MustCompile(`^(\d{1,4})[-/.](\d{1,2})(?:[-/.](\d{1,2}))?$`)
+	calendarDayFirst = regexp.MustCompile(`^(?:(\d{1,2}) )?([\p{L}' -]+?) (\d{1,4})$`)
+	calendarNamed    = regexp.MustCompile(`^([\p{L}' -]+?) (\d{1,2}),? (\d{1,4})$`)
+	calendarYear     = regexp.MustCompile(`^(\d{1,4})$`)
+)
+
+// calendarDate reads the year, month and day of s, looking month names up
+// with month; month and day are 0 when not given
+func calendarDate(s string, month func(string) int) (year, m, day int, ok bool) {
+	if match := calendarNumeric.FindStringSubmatch(s); match != nil {
+		year, _ = strconv.Atoi(match[1])
+		m, _ = strconv.Atoi(match[2])
+		day, _ = strconv.Atoi(match[3])
+		return year, m, day, true
+	}
+	if match := calendarNamed.FindStringSubmatch(s); match != nil {
+		if m = month(match[1]); m != 0 {
+			day, _ = strconv.Atoi(match[2])
+			year, _ = strconv.Atoi(match[3])
+			return year, m, day, true
+		}
+	}
+	if match := calendarDayFirst.FindStringSubmatch(s); match != nil {
+		if m = month(match[2]); m != 0 {
+			day, _ = strconv.Atoi(match[1])
+			year, _ = strconv.Atoi(match[3])
+			return year, m, day, true
+		}
+	}
+	if match := calendarYear.FindStringSubmatch(s); match != nil {
+		year, _ = strconv.Atoi(match[1])
+		return year, 0, 0, true
+	}
+	return 0, 0, 0, false
+}
+
+// stripMarkers removes the marker words from s, reporting whether any was found
+func stripMarkers(s string, markers map[string]bool) (string, bool) {
+	fields := strings.Fields(strings.ToLower(s))
+	kept := fields[:0]
+	found := false
+	for _, field := range fields {
+		if markers[strings.Trim(field, "(),")] {
+			found = true
+			continue
+		}
+		kept = append(kept, field)
+	}
+	return strings.TrimSuffix(strings.Join(kept, " "), ","), found
+}
+
+// calendarPeriod builds a CalendarDate covering the day, month or year given
+func calendarPeriod(name, era, text string, year, month, day int, start, end time.Time) CalendarDate {
+	precision := PrecisionYear
+	switch {
+	case day != 0:
+		precision = PrecisionDay
+	case month != 0:
+		precision = PrecisionMonth
+	}
+	return CalendarDate{
+		Calendar: name, Era: era, Year: year, Month: month, Day: day, Text: text,
+		Period: Interval{Start: start, End: end}, Precision: precision,
+	}
+}
+
+// JulianCalendar reads Julian calendar dates marked "Julian" or "O.S." (old
+// style), such as "1 March 1500 Julian" or "1500-02-29 (O.S.)"
+type JulianCalendar struct{}
+
+var julianMarkers = map[string]bool{"julian": true, "os": true, "o.s.": true, "o.s": true}
+
+var julianMonths = []string{"January", "February", "March", "April", "May", "June",
+	"July", "August", "September", "October", "November", "December"}
+
+func (JulianCalendar) Name() string { return "julian" }
+
+func (c JulianCalendar) Convert(value string, loc *time.Location) (CalendarDate, bool, error) {
+	s, marked := stripMarkers(value, julianMarkers)
+	if !marked {
+		return CalendarDate{}, false, nil
+	}
+	english, _ := LookupLocale(DefaultLocale)
+	year, month, day, ok := calendarDate(s, func(name string) int { return int(english.months[name]) })
+	if !ok || year == 0 {
+		return CalendarDate{}, true, fmt.Errorf("invalid Julian date %q, expected e.g. 1 March 1500 Julian", value)
+	}
+
+	if month > 12 || day > julianMonthDays(year, month) {
+		return CalendarDate{}, true, fmt.Errorf("invalid Julian date %q: no such day", value)
+	}
+
+	var start, end time.Time
+	switch {
+	case day != 0:
+		start = fromJDN(julianJDN(year, month, day), loc)
+		end = start.AddDate(0, 0, 1)
+	case month != 0:
+		start = fromJDN(julianJDN(year, month, 1), loc)
+		end = start.AddDate(0, 0, julianMonthDays(year, month))
+	default:
+		start = fromJDN(julianJDN(year, 1, 1), loc)
+		end = fromJDN(julianJDN(year+1, 1, 1), loc)
+	}
+	return calendarPeriod(c.Name(), "", value, year, month, day, start, end), true, nil
+}
+
+func julianMonthDays(year, month int) int {
+	switch month {
+	case 0:
+		return 0
+	case 2:
+		if year%4 == 0 {
+			return 29
+		}
+		return 28
+	case 4, 6, 9, 11:
+		return 30
+	default:
+		return 31
+	}
+}
+
+// julianJDN returns the Julian day number of a Julian calendar date
+func julianJDN(year, month, day int) int {
+	a := (14 - month) / 12
+	y := year + 4800 - a
+	m := month + 12*a - 3
+	return day + (153*m+2)/5 + 365*y + y/4 - 32083
+}
+
+// IslamicCalendar reads dates of the tabular Islamic civil calendar (Hijri),
+// marked "AH", "Hijri" or an H attached to the year, or using an Islamic
+// month name, such as "15 Ramadan 1445 AH", "1445-09-15 AH" or "1445H". Observed dates may differ by a day
+// or two from the tabular ones.
+type IslamicCalendar struct{}
+
+var islamicMarkers = map[string]bool{"ah": true, "a.h.": true, "a.h": true, "hijri": true}
+
+// attachedHijri matches an H marker attached to a 3 or 4 digit year standing
+// as its own word, as in "Ramadan 1445H". A separate H, as in "5 h", or one
+// attached to other numbers, as in "1500h", is too weak a sign of an Islamic
+// date.
+var attachedHijri = regexp.MustCompile(`(?:^|\s)\d{3,4}h$`)
+
+var islamicMonths = []string{"Muharram", "Safar", "Rabi al-Awwal", "Rabi al-Thani", "Jumada al-Awwal", "Jumada al-Thani",
+	"Rajab", "Shaban", "Ramadan", "Shawwal", "Dhu al-Qadah", "Dhu al-Hijjah"}
+
+// islamicMonthNames maps spellings, without punctuation or spaces, to months
+var islamicMonthNames = map[string]int{
+	"muharram": 1, "muharam": 1,
+	"safar":       2,
+	"rabialawwal": 3, "rabiulawal": 3, "rabiulawwal": 3, "rabii": 3,
+	"rabialthani": 4, "rabialakhir": 4, "rabiulakhir": 4, "rabiuthani": 4, "rabiii": 4,
+	"jumadaalawwal": 5, "jumadaalula": 5, "jumadilawal": 5, "jumadai": 5,
+	"jumadaalthani": 6, "jumadaalakhirah": 6, "jumadilakhir": 6, "jumadaii": 6,
+	"rajab":  7,
+	"shaban": 8, "syaban": 8,
+	"ramadan": 9, "ramadhan": 9, "ramazan": 9,
+	"shawwal": 10, "syawal": 10,
+	"dhualqadah": 11, "dhulqadah": 11, "dhualqidah": 11, "dhulqidah": 11, "zulkaidah": 11,
+	"dhualhijjah": 12, "dhulhijjah": 12, "zulhijah": 12,
+}
+
+func islamicMonth(name string) int {
+	key := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, strings.ToLower(name))
+	return islamicMonthNames[key]
+}
+
+func (IslamicCalendar) Name() string { return "islamic" }
+
+func (c IslamicCalendar) Convert(value string, loc *time.Location) (CalendarDate, bool, error) {
+	s, marked := stripMarkers(value, islamicMarkers)
+	if !marked && attachedHijri.MatchString(s) {
+		// The H only counts next to a month name, so a bare "1500h" is no date
+		trimmed := s[:len(s)-1]
+		if _, month, _, ok := calendarDate(trimmed, islamicMonth); ok && month != 0 && !calendarNumeric.MatchString(trimmed) {
+			s, marked = trimmed, true
+		}
+	}
+	year, month, day, ok := calendarDate(s, islamicMonth)
+	named := ok && month != 0 && !calendarNumeric.MatchString(s)
+	if !marked && !named {
+		return CalendarDate{}, false, nil
+	}
+	if !ok || year == 0 || month > 12 || day > islamicMonthDays(year, month) {
+		return CalendarDate{}, true, fmt.Errorf("invalid Islamic date %q, expected e.g. 15 Ramadan 1445 AH", value)
+	}
+
+	var start, end time.Time
+	switch {
+	case day != 0:
+		start = fromJDN(islamicJDN(year, month, day), loc)
+		end = start.AddDate(0, 0, 1)
+	case month != 0:
+		start = fromJDN(islamicJDN(year, month, 1), loc)
+		end = start.AddDate(0, 0, islamicMonthDays(year, month))
+	default:
+		start = fromJDN(islamicJDN(year, 1, 1), loc)
+		end = fromJDN(islamicJDN(year+1, 1, 1), loc)
+	}
+	return calendarPeriod(c.Name(), "AH", value, year, month, day, start, end), true, nil
+}
+
+// islamicMonthDays alternates 30 and 29 days, with 30 in the last month of
+// the 11 leap years of each 30-year cycle
+func islamicMonthDays(year, month int) int {
+	switch {
+	case month == 0:
+		return 0
+	case month%2 == 1:
+		return 30
+	case month == 12 && (14+11*year)%30 < 11:
+		return 30
+	default:
+		return 29
+	}
+}
+
+// islamicJDN returns the Julian day number of a tabular Islamic date, with
+// 1 Muharram 1 AH on July 16, 622 (Julian)
+func islamicJDN(year, month, day int) int {
+	return day + (59*(month-1)+1)/2 + (year-1)*354 + (3+11*year)/30 + 1948439
+}
+
+// JapaneseCalendar reads Japanese era dates, such as "Reiwa 3", "Heisei
+// 31-04-30", "H31.4.30" or "令和3年5月1日"
+type JapaneseCalendar struct{}
+
+type japaneseEra struct {
+	name, kanji string
+	letter      byte
+	start       time.Time
+}
+
+// japaneseEras are the modern eras, latest first. Dates before 1873 used a
+// lunisolar calendar and are read as Gregorian.
+var japaneseEras = []japaneseEra{
+	{"Reiwa", "令和", 'r', time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)},
+	{"Heisei", "平成", 'h', time.Date(1989, 1, 8, 0, 0, 0, 0, time.UTC)},
+	{"Showa", "昭和", 's', time.Date(1926, 12, 25, 0, 0, 0, 0, time.UTC)},
+	{"Taisho", "大正", 't', time.Date(1912, 7, 30, 0, 0, 0, 0, time.UTC)},
+	{"Meiji", "明治", 'm', time.Date(1868, 10, 23, 0, 0, 0, 0, time.UTC)},
+}
+
+var (
+	japaneseDate = regexp.MustCompile(`^(\d{1,2}|gannen|元)(?:[ .\-/年]+(\d{1,2})(?:[ .\-/月]+(\d{1,2})日?)?月?)?年?$`)
+	romanized    = strings.NewReplacer("ō", "o", "ô", "o", "ou", "o", "oo", "o")
+)
+
+func (JapaneseCalendar) Name() string { return "japanese" }
+
+func (c JapaneseCalendar) Convert(value string, loc *time.Location) (CalendarDate, bool, error) {
+	s := strings.TrimSpace(value)
+	lower := romanized.Replace(strings.ToLower(s))
+
+	var era *japaneseEra
+	var rest string
+	abbreviated := false
+	for i := range japaneseEras {
+		e := &japaneseEras[i]
+		if after, ok := strings.CutPrefix(s, e.kanji); ok {
+			era, rest = e, after
+			break
+		}
+		if after, ok := strings.CutPrefix(lower, strings.ToLower(e.name)); ok && (after == "" || !isLetter(after[0])) {
+			era, rest = e, after
+			break
+		}
+		// Abbreviations such as H31.4.30 need a digit right after the letter
+		if len(lower) > 1 && lower[0] == e.letter && isDigit(lower[1]) {
+			era, rest, abbreviated = e, lower[1:], true
+			break
+		}
+	}
+	if era == nil {
+		return CalendarDate{}, false, nil
+	}
+
+	m := japaneseDate.FindStringSubmatch(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), ",")))
+	if abbreviated && (m == nil || m[3] == "") {
+		// A lone letter and number, such as T10, is too weak a sign of an era
+		return CalendarDate{}, false, nil
+	}
+	if m == nil {
+		return CalendarDate{}, true, fmt.Errorf("invalid Japanese era date %q, expected e.g. Reiwa 3 or Reiwa 3-05-01", value)
+	}
+	year := 1
+	if m[1] != "gannen" && m[1] != "元" {
+		year, _ = strconv.Atoi(m[1])
+	}
+	month, _ := strconv.Atoi(m[2])
+	day, _ := strconv.Atoi(m[3])
+	if year == 0 || month > 12 || day > 31 {
+		return CalendarDate{}, true, fmt.Errorf("invalid Japanese era date %q", value)
+	}
+
+	gregorian := era.start.Year() + year - 1
+	var start, end time.Time
+	switch {
+	case day != 0:
+		start = time.Date(gregorian, time.Month(month), day, 0, 0, 0, 0, loc)
+		if start.Day() != day {
+			return CalendarDate{}, true, fmt.Errorf("invalid Japanese era date %q: no such day", value)
+		}
+		end = start.AddDate(0, 0, 1)
+	case month != 0:
+		start = time.Date(gregorian, time.Month(month), 1, 0, 0, 0, 0, loc)
+		end = start.AddDate(0, 1, 0)
+	default:
+		start = time.Date(gregorian, 1, 1, 0, 0, 0, 0, loc)
+		end = start.AddDate(1, 0, 0)
+	}
+
+	// Clip the period to the era, rejecting dates outside it
+	eraStart := time.Date(era.start.Year(), era.start.Month(), era.start.Day(), 0, 0, 0, 0, loc)
+	if start.Before(eraStart) {
+		start = eraStart
+	}
+	for i := range japaneseEras {
+		if &japaneseEras[i] == era && i > 0 {
+			next := japaneseEras[i-1].start
+			eraEnd := time.Date(next.Year(), next.Month(), next.Day(), 0, 0, 0, 0, loc)
+			if end.After(eraEnd) {
+				end = eraEnd
+			}
+		}
+	}
+	if !start.Before(end) {
+		return CalendarDate{}, true, fmt.Errorf("invalid Japanese era date %q: outside the %s era", value, era.name)
+	}
+
+	return calendarPeriod(c.Name(), era.name, value, year, month, day, start, end), true, nil
+}
@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendars(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		input      string
+		calendar   string
+		start, end time.Time
+		display    string
+	}{
+		// The day after 4 October 1582 (Julian) was 15 October 1582 (Gregorian)
+		{"4 October 1582 Julian", "julian", date(1582, 10, 14), date(1582, 10, 15), "4 October 1582 (julian)"},
+		{"1500-02-29 (O.S.)", "julian", date(1500, 3, 10), date(1500, 3, 11), "29 February 1500 (julian)"},
+		{"Julian 1300", "julian", date(1300, 1, 8), date(1301, 1, 9), "1300 (julian)"},
+		{"1 Ramadan 1445 AH", "islamic", date(2024, 3, 11), date(2024, 3, 12), "1 Ramadan 1445 AH"},
+		{"1 Muharram 1 AH", "islamic", date(622, 7, 19), date(622, 7, 20), "1 Muharram 1 AH"},
+		{"Dhu al-Hijjah 1444", "islamic", date(2023, 6, 20), date(2023, 7, 19), "Dhu al-Hijjah 1444 AH"},
+		{"1445-09-15 AH", "islamic", date(2024, 3, 25), date(2024, 3, 26), "15 Ramadan 1445 AH"},
+		{"15 Ramadan 1445H", "islamic", date(2024, 3, 25), date(2024, 3, 26), "15 Ramadan 1445 AH"},
+		{"Ramadan 1445h", "islamic", date(2024, 3, 11), date(2024, 4, 10), "Ramadan 1445 AH"},
+		{"Reiwa 3", "japanese", date(2021, 1, 1), date(2022, 1, 1), "Reiwa 3"},
+		{"Reiwa 1", "japanese", date(2019, 5, 1), date(2020, 1, 1), "Reiwa 1"},
+		{"Heisei 31-04-30", "japanese", date(2019, 4, 30), date(2019, 5, 1), "Heisei 31-04-30"},
+		{"H31.4.30", "japanese", date(2019, 4, 30), date(2019, 5, 1), "Heisei 31-04-30"},
+		{"Shōwa gannen", "japanese", date(1926, 12, 25), date(1927, 1, 1), "Showa 1"},
+		{"令和3年5月1日", "japanese", date(2021, 5, 1), date(2021, 5, 2), "Reiwa 3-05-01"},
+	}
+
+	p := NewDateTimeParser()
+	for _, tt := range tests {
+		got, err := p.ParseDetailed(tt.input)
+		if err != nil {
+			t.Errorf("parse %q: %v", tt.input, err)
+			continue
+		}
+		if got.Calendar == nil || got.Calendar.Calendar != tt.calendar {
+			t.Errorf("parse %q: calendar %+v, want %s", tt.input, got.Calendar, tt.calendar)
+			continue
+		}
+		if !got.Time.Equal(tt.start) || !got.End.Equal(tt.end) {
+			t.Errorf("parse %q = %s, want %s", tt.input, got.Interval(), Interval{tt.start, tt.end})
+		}
+		if got.Calendar.String() != tt.display || got.Calendar.Text != tt.input {
+			t.Errorf("parse %q: shown as %q (%q), want %q", tt.input, got.Calendar, got.Calendar.Text, tt.display)
+		}
+	}
+
+	for _, input := range []string{"1500-02-30 Julian", "30 Dhu al-Hijjah 1444 AH", "Heisei 31-05-01", "Showa 64-01-08", "Reiwa 0", "5 h", "1445 H", "1500h", "1445-09-15h", "15 Ramadan 21h"} {
+		if _, err := p.Parse(input); err == nil {
+			t.Errorf("parse %q accepted an invalid date", input)
+		}
+	}
+
+	if _, err := NewDateTimeParser(WithCalendars()).Parse("Reiwa 3"); err == nil {
+		t.Error("parser without calendars read a Japanese era date")
+	}
+}
@@ -2,198 +2,381 @@ package parser
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// DateTimeParser handles parsing various date/time formats
-type DateTimeParser struct {
-	formats []string
-	mu      sync.RWMutex
-}
+// DateOrder decides how numeric dates such as 03/04/2020 are read
+type DateOrder int
+
+const (
+	// OrderAuto reads month-first and falls back to day-first when the first
+	// number cannot be a month, e.g. 13/04/2020
+	OrderAuto DateOrder = iota
+	// OrderMonthFirst reads MM/DD/YYYY only
+	OrderMonthFirst
+	// OrderDayFirst reads DD/MM/YYYY only
+	OrderDayFirst
+)
 
-var (
-	instance *DateTimeParser
-	once     sync.Once
+// Request headers selecting the parse options per request
+const (
+	DateOrderHeader      = "X-Date-Order"
+	DateStrictHeader     = "X-Date-Strict"
+	AcceptLanguageHeader = "Accept-Language"
 )
 
-// GetParser returns the singleton instance of DateTimeParser
-func GetParser() *DateTimeParser {
-	once.Do(func() {
-		instance = &DateTimeParser{
-			formats: []string{
-				// RFC3339 and ISO 8601 variants
-				time.RFC3339,
-				time.RFC3339Nano,
-				"2006-01-02T15:04:05Z07:00",
-				"2006-01-02T15:04:05.000Z07:00",
-				"2006-01-02T15:04:05Z",
-				"2006-01-02T15:04:05.000Z",
-				"2006-01-02T15:04:05",
-				"2006-01-02T15:04:05.000",
-
-				// Date only formats
-				"2006-01-02",
-				"01/02/2006",
-				"1/2/2006",
-				"02/01/2006", // DD/MM/YYYY
-				"2/1/2006",   // D/M/YYYY
-				"2006/01/02",
-				"2006/1/2",
-				"01-02-2006",
-				"1-2-2006",
-				"02-01-2006", // DD-MM-YYYY
-				"2-1-2006",   // D-M-YYYY
-				"2006-01-02",
-				"Jan 2, 2006",
-				"January 2, 2006",
-				"Jan 02, 2006",
-				"January 02, 2006",
-				"2 Jan 2006",
-				"02 Jan 2006",
-				"2 January 2006",
-				"02 January 2006",
-
-				// Time with date formats
-				"2006-01-02 15:04:05",
-				"2006-01-02 15:04:05.000",
-				"01/02/2006 15:04:05",
-				"1/2/2006 15:04:05",
-				"01/02/2006 3:04:05 PM",
-				"1/2/2006 3:04:05 PM",
-				"2006-01-02 3:04:05 PM",
-				"Jan 2, 2006 15:04:05",
-				"Jan 2, 2006 3:04:05 PM",
-				"January 2, 2006 15:04:05",
-				"January 2, 2006 3:04:05 PM",
-
-				// Time only formats (will use current date)
-				"15:04:05",
-				"15:04:05.000",
-				"3:04:05 PM",
-				"3:04 PM",
-				"15:04",
-
-				// Unix timestamp handling is done separately
-			},
+// DateWarningHeader is the response header telling the client that an
+// ambiguous numeric date was read month-first
+const DateWarningHeader = "X-Date-Warning"
+
+// ParseDateOrder reads "month-first" (or "mdy"), "day-first" (or "dmy") and
+// "auto"; an empty value is OrderAuto
+func ParseDateOrder(value string) (DateOrder, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "auto":
+		return OrderAuto, nil
+	case "month-first", "mdy":
+		return OrderMonthFirst, nil
+	case "day-first", "dmy":
+		return OrderDayFirst, nil
+	default:
+		return OrderAuto, fmt.Errorf("invalid date order %q, expected month-first, day-first or auto", value)
+	}
+}
+
+// String returns the name accepted by ParseDateOrder
+func (o DateOrder) String() string {
+	switch o {
+	case OrderMonthFirst:
+		return "month-first"
+	case OrderDayFirst:
+		return "day-first"
+	default:
+		return "auto"
+	}
+}
+
+// ParseOptions controls how ambiguous input is handled
+type ParseOptions struct {
+	Order DateOrder
+	// Strict rejects dates whose day and month could be swapped instead of
+	// reading them month-first. It only applies to OrderAuto.
+	Strict bool
+	// Locale selects the language of month and weekday names, such as "fr";
+	// English names are always understood
+	Locale string
+	// UnixUnit is the unit of Unix timestamps without a unit suffix
+	UnixUnit UnixUnit
+}
+
+// OptionsFromHeaders builds parse options from the X-Date-Order,
+// X-Date-Strict and Accept-Language header values
+func OptionsFromHeaders(order, strict, acceptLanguage string) (ParseOptions, error) {
+	return ParseOptions{}.WithHeaders(order, strict, acceptLanguage)
+}
+
+// WithHeaders returns the options overridden by the X-Date-Order,
+// X-Date-Strict and Accept-Language header values that are set
+func (o ParseOptions) WithHeaders(order, strict, acceptLanguage string) (ParseOptions, error) {
+	var err error
+	if order != "" {
+		if o.Order, err = ParseDateOrder(order); err != nil {
+			return o, err
+		}
+	}
+	if strict != "" {
+		if o.Strict, err = strconv.ParseBool(strict); err != nil {
+			return o, fmt.Errorf("invalid %s header %q", DateStrictHeader, strict)
 		}
-	})
-	return instance
+	}
+	if locale := LocaleFromAcceptLanguage(acceptLanguage); locale != "" {
+		o.Locale = locale
+	}
+	return o, nil
+}
+
+// AmbiguousDateError is returned in strict mode for numeric dates that are
+// valid both month-first and day-first, and set as Result.Ambiguity otherwise
+type AmbiguousDateError struct {
+	Input      string
+	MonthFirst time.Time
+	DayFirst   time.Time
+}
+
+func (e *AmbiguousDateError) Error() string {
+	return fmt.Sprintf("ambiguous date %q: it is %s read month-first or %s read day-first; set the date order to month-first or day-first",
+		e.Input, e.MonthFirst.Format("January 2, 2006"), e.DayFirst.Format("January 2, 2006"))
+}
+
+// Warning describes the month-first reading chosen outside strict mode
+func (e *AmbiguousDateError) Warning() string {
+	return fmt.Sprintf(WarningAmbiguousDate, e.Input, e.MonthFirst.Format("January 2, 2006"), e.DayFirst.Format("January 2, 2006"))
+}
+
+// DateTimeParser handles parsing various date/time formats. Create one with
+// NewDateTimeParser; its settings are fixed apart from AddFormat.
+type DateTimeParser struct {
+	// formats is replaced, never changed, by AddFormat, so parsing loads it
+	// without locking
+	formats atomic.Pointer[layoutSet]
+	// monthFirst and dayFirst hold the numeric layouts whose reading depends on the date order
+	monthFirst *layoutSet
+	dayFirst   *layoutSet
+	options    ParseOptions
+	// location is the zone of input without a UTC offset
+	location *time.Location
+	// calendars convert non-Gregorian dates
+	calendars []Calendar
+	// format is the output format of responses
+	format DateFormat
+	// now is the clock relative expressions such as "yesterday" are evaluated against
+	now func() time.Time
+	// mu serializes AddFormat
+	mu sync.Mutex
+}
+
+// Options returns the options used by Parse and ParseWithLocation
+func (p *DateTimeParser) Options() ParseOptions {
+	return p.options
+}
+
+// DateFormat returns the format responses write times in by default
+func (p *DateTimeParser) DateFormat() DateFormat {
+	return p.format
 }
 
 // AddFormat adds a custom format to the parser (thread-safe)
 func (p *DateTimeParser) AddFormat(format string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	p.formats = append(p.formats, format)
+	p.formats.Store(p.formats.Load().with(format))
 }
 
 // Parse attempts to parse a date/time string using various formats
 func (p *DateTimeParser) Parse(dateStr string) (time.Time, error) {
+	return p.ParseWithOptions(dateStr, p.Options())
+}
+
+// ParseWithOptions parses like Parse with the given options instead of the
+// parser's own
+func (p *DateTimeParser) ParseWithOptions(dateStr string, opts ParseOptions) (time.Time, error) {
+	result, err := p.ParseDetailedWithOptions(dateStr, opts)
+	return result.Time, err
+}
+
+// ParseDetailed parses like Parse and also reports the matched layout,
+// precision, whether the offset was explicit and any warnings
+func (p *DateTimeParser) ParseDetailed(dateStr string) (Result, error) {
+	return p.ParseDetailedWithOptions(dateStr, p.Options())
+}
+
+// ParseDetailedWithOptions is ParseDetailed with the given options instead of
+// the parser's own
+func (p *DateTimeParser) ParseDetailedWithOptions(dateStr string, opts ParseOptions) (Result, error) {
 	// Trim whitespace
 	dateStr = strings.TrimSpace(dateStr)
 
 	if dateStr == "" {
-		return time.Time{}, fmt.Errorf("empty date string")
+		return Result{}, fmt.Errorf("empty date string")
 	}
 
 	// Try to parse as Unix timestamp first
-	if t, err := p.parseUnixTimestamp(dateStr); err == nil {
-		return t, nil
+	if result, err := parseUnixTimestamp(dateStr, opts.UnixUnit); err != errNotTimestamp {
+		return result, err
 	}
 
-	// Get formats in a thread-safe way
-	p.mu.RLock()
-	formats := make([]string, len(p.formats))
-	copy(formats, p.formats)
-	p.mu.RUnlock()
+	formats := p.formats.Load()
+	now := func() time.Time { return p.now().In(p.location) }
 
-	// Try each format
-	for _, format := range formats {
-		if t, err := time.Parse(format, dateStr); err == nil {
-			return t, nil
-		}
+	parse := func(formats *layoutSet) (time.Time, string, bool) {
+		return formats.match(dateStr, p.parseLayout)
 	}
 
-	// If no format worked, try with different locations
-	locations := []*time.Location{
-		time.UTC,
-		time.Local,
-	}
-
-	for _, loc := range locations {
-		for _, format := range formats {
-			if t, err := time.ParseInLocation(format, dateStr, loc); err == nil {
-				return t, nil
+	var result Result
+	// The first layout, RFC 3339 by default, wins whenever it accepts the
+	// input, so likely input is tried against it before being classified
+	if t, ok := p.parseFirst(formats, dateStr); ok {
+		result = describeTraits(formats.layouts[0], formats.firstTraits, t)
+	} else if t, layout, ok := parse(formats); ok {
+		result = describeLayout(layout, t)
+	} else {
+		t, layout, ambiguity, err := p.parseOrdered(dateStr, opts, parse)
+		if _, ambiguous := err.(*AmbiguousDateError); err != nil && !ambiguous {
+			// The fallbacks are slow, so input without a word they know is
+			// only given to those that could still read it
+			known, letters := fallbackHints(dateStr)
+			if known || !letters && tidiedNumeric(dateStr) {
+				if localized, ok := p.parseLocalized(formats, dateStr, opts.Locale, now()); ok {
+					return localized, nil
+				}
+			}
+			for _, calendar := range p.calendars {
+				if !known && builtinCalendar(calendar) {
+					continue
+				}
+				if date, ok, calendarErr := calendar.Convert(dateStr, p.location); ok {
+					if calendarErr != nil {
+						return Result{}, calendarErr
+					}
+					return calendarResult(date), nil
+				}
 			}
 		}
+		if err != nil && opts.UnixUnit == UnixAuto && isShortNumber(dateStr) {
+			return Result{}, fmt.Errorf("%w; numbers of fewer than %d digits are read as Unix timestamps only with a sign, a unit suffix (s, ms, us, ns) or a fixed unit", err, minAutoDigits)
+		}
+		if err != nil {
+			return Result{}, err
+		}
+		result = describeLayout(layout, t)
+		if ambiguity != nil {
+			result.Ambiguity = ambiguity
+			result.Warnings = append(result.Warnings, ambiguity.Warning())
+		}
 	}
 
-	return time.Time{}, fmt.Errorf("unable to parse date string: %s", dateStr)
+	if !result.ExplicitOffset && result.Precision >= PrecisionHour {
+		result.Warnings = append(result.Warnings, fmt.Sprintf(WarningAssumedZone, result.Time.Location()))
+	}
+	return result, nil
 }
 
-// parseUnixTimestamp attempts to parse Unix timestamps
-func (p *DateTimeParser) parseUnixTimestamp(dateStr string) (time.Time, error) {
-	// Check if it looks like a Unix timestamp (all digits, possibly with decimal)
-	if !isNumeric(dateStr) {
-		return time.Time{}, fmt.Errorf("not a numeric timestamp")
-	}
+// ParseRange parses dateStr into the period it denotes: a day for
+// "2020-01-02", a quarter for "Q3 2021", or an instant for a full timestamp
+func (p *DateTimeParser) ParseRange(dateStr string) (Interval, error) {
+	return p.ParseRangeWithOptions(dateStr, p.Options())
+}
+
+// ParseRangeWithOptions is ParseRange with the given options instead of the
+// parser's own
+func (p *DateTimeParser) ParseRangeWithOptions(dateStr string, opts ParseOptions) (Interval, error) {
+	result, err := p.ParseDetailedWithOptions(dateStr, opts)
+	return result.Interval(), err
+}
 
-	// Try parsing as Unix timestamp (seconds)
-	if len(dateStr) == 10 {
-		var sec int64
-		if _, err := fmt.Sscanf(dateStr, "%d", &sec); err == nil {
-			return time.Unix(sec, 0), nil
+// parseLocalized reads dateStr with month and weekday names in the given
+// locale, then in English, and tries natural-language expressions last
+func (p *DateTimeParser) parseLocalized(formats *layoutSet, dateStr, tag string, now time.Time) (Result, bool) {
+	candidates := []string{dateStr}
+	for _, tag := range []string{tag, DefaultLocale} {
+		if locale, ok := LookupLocale(tag); ok {
+			if normalized := locale.normalize(dateStr); normalized != candidates[len(candidates)-1] {
+				candidates = append(candidates, normalized)
+			}
 		}
 	}
 
-	// Try parsing as Unix timestamp with milliseconds
-	if len(dateStr) == 13 {
-		var msec int64
-		if _, err := fmt.Sscanf(dateStr, "%d", &msec); err == nil {
-			return time.Unix(msec/1000, (msec%1000)*1000000), nil
+	for _, candidate := range candidates[1:] {
+		if t, layout, ok := formats.match(candidate, p.parseLayout); ok {
+			result := describeLayout(layout, t)
+			if !result.ExplicitOffset && result.Precision >= PrecisionHour {
+				result.Warnings = append(result.Warnings, fmt.Sprintf(WarningAssumedZone, result.Time.Location()))
+			}
+			return result, true
 		}
 	}
-
-	// Try parsing as Unix timestamp with microseconds
-	if len(dateStr) == 16 {
-		var usec int64
-		if _, err := fmt.Sscanf(dateStr, "%d", &usec); err == nil {
-			return time.Unix(usec/1000000, (usec%1000000)*1000), nil
+	for _, candidate := range candidates {
+		if natural, ok := parseNatural(candidate, now); ok {
+			return naturalResult(natural, now), true
 		}
 	}
+	return Result{}, false
+}
 
-	// Try parsing as Unix timestamp with nanoseconds
-	if len(dateStr) == 19 {
-		var nsec int64
-		if _, err := fmt.Sscanf(dateStr, "%d", &nsec); err == nil {
-			return time.Unix(nsec/1000000000, nsec%1000000000), nil
-		}
+// parseFirst parses value with the first layout of formats when it looks
+// like its output
+func (p *DateTimeParser) parseFirst(formats *layoutSet, value string) (time.Time, bool) {
+	if !formats.looksFirst(value) {
+		return time.Time{}, false
 	}
+	t, err := p.parseLayout(formats.layouts[0], value)
+	return t, err == nil
+}
 
-	return time.Time{}, fmt.Errorf("invalid timestamp format")
+// parseLayout parses value with layout, in the parser's location when the
+// input has no UTC offset
+func (p *DateTimeParser) parseLayout(layout, value string) (time.Time, error) {
+	return parseInLocation(layout, value, p.location)
 }
 
-// isNumeric checks if a string contains only digits and optionally one decimal point
-func isNumeric(s string) bool {
-	if s == "" {
-		return false
+// parseInLocation is time.ParseInLocation, reading a literal trailing Z in
+// layout as UTC rather than loc
+func parseInLocation(layout, value string, loc *time.Location) (time.Time, error) {
+	if literalZ(layout) {
+		loc = time.UTC
 	}
+	return time.ParseInLocation(layout, value, loc)
+}
 
-	decimalCount := 0
-	for _, r := range s {
-		if r == '.' {
-			decimalCount++
-			if decimalCount > 1 {
-				return false
-			}
-		} else if r < '0' || r > '9' {
-			return false
+// calendarResult describes a date converted from another calendar
+func calendarResult(date CalendarDate) Result {
+	return Result{
+		Time:      date.Period.Start,
+		End:       date.Period.End,
+		Layout:    date.Calendar,
+		Precision: date.Precision,
+		Calendar:  &date,
+	}
+}
+
+// naturalResult describes a natural-language expression
+func naturalResult(natural naturalDate, now time.Time) Result {
+	r := Result{
+		Time:      natural.interval.Start,
+		End:       natural.interval.End,
+		Layout:    "natural",
+		Precision: natural.precision,
+	}
+	if natural.relative {
+		r.Warnings = append(r.Warnings, fmt.Sprintf(WarningRelative, now.Format(time.RFC3339)))
+	}
+	return r
+}
+
+// parseOrdered parses numeric dates whose reading depends on the date order,
+// using parse to try a list of layouts. Both readings are returned when an
+// ambiguous date was read month-first.
+func (p *DateTimeParser) parseOrdered(dateStr string, opts ParseOptions, parse func(*layoutSet) (time.Time, string, bool)) (time.Time, string, *AmbiguousDateError, error) {
+	switch opts.Order {
+	case OrderMonthFirst:
+		if t, layout, ok := parse(p.monthFirst); ok {
+			return t, layout, nil, nil
 		}
+		return time.Time{}, "", nil, fmt.Errorf("unable to parse date string as month-first (MM/DD/YYYY): %s", dateStr)
+	case OrderDayFirst:
+		if t, layout, ok := parse(p.dayFirst); ok {
+			return t, layout, nil, nil
+		}
+		return time.Time{}, "", nil, fmt.Errorf("unable to parse date string as day-first (DD/MM/YYYY): %s", dateStr)
+	}
+
+	monthFirst, monthLayout, monthOK := parse(p.monthFirst)
+	dayFirst, dayLayout, dayOK := parse(p.dayFirst)
+	ambiguous := monthOK && dayOK && !monthFirst.Equal(dayFirst)
+	switch {
+	case ambiguous && opts.Strict:
+		return time.Time{}, "", nil, &AmbiguousDateError{Input: dateStr, MonthFirst: monthFirst, DayFirst: dayFirst}
+	case ambiguous:
+		return monthFirst, monthLayout, &AmbiguousDateError{Input: dateStr, MonthFirst: monthFirst, DayFirst: dayFirst}, nil
+	case monthOK:
+		return monthFirst, monthLayout, nil, nil
+	case dayOK:
+		return dayFirst, dayLayout, nil, nil
 	}
 
-	return true
+	return time.Time{}, "", nil, fmt.Errorf("unable to parse date string: %s", dateStr)
+}
+
+// IsAmbiguous reports whether dateStr is a numeric date that reads as two
+// different days month-first and day-first, such as 03/04/2020
+func (p *DateTimeParser) IsAmbiguous(dateStr string) bool {
+	_, err := p.ParseWithOptions(dateStr, ParseOptions{Order: OrderAuto, Strict: true})
+	_, ambiguous := err.(*AmbiguousDateError)
+	return ambiguous
 }
 
 // ParseWithLocation parses a date/time string with a specific timezone location
@@ -205,39 +388,58 @@ func (p *DateTimeParser) ParseWithLocation(dateStr string, loc *time.Location) (
 	}
 
 	// Try to parse as Unix timestamp first
-	if t, err := p.parseUnixTimestamp(dateStr); err == nil {
-		return t.In(loc), nil
+	if result, err := parseUnixTimestamp(dateStr, p.Options().UnixUnit); err != errNotTimestamp {
+		return result.Time.In(loc), err
 	}
 
-	// Get formats in a thread-safe way
-	p.mu.RLock()
-	formats := make([]string, len(p.formats))
-	copy(formats, p.formats)
-	p.mu.RUnlock()
+	formats := p.formats.Load()
 
-	// Try each format with the specified location
-	for _, format := range formats {
-		if t, err := time.ParseInLocation(format, dateStr, loc); err == nil {
-			return t, nil
-		}
+	parse := func(formats *layoutSet) (time.Time, string, bool) {
+		return formats.match(dateStr, func(layout, value string) (time.Time, error) {
+			return parseInLocation(layout, value, loc)
+		})
 	}
 
-	return time.Time{}, fmt.Errorf("unable to parse date string: %s", dateStr)
+	if t, _, ok := parse(formats); ok {
+		return t, nil
+	}
+	t, _, _, err := p.parseOrdered(dateStr, p.Options(), parse)
+	return t, err
 }
 
-// Convenience functions for direct usage
+// Convenience functions using the default parser
 
-// Parse parses a date/time string using the singleton instance
+// ParseDate parses a date/time string using the default parser
 func ParseDate(dateStr string) (time.Time, error) {
-	return GetParser().Parse(dateStr)
+	return Default().Parse(dateStr)
+}
+
+// ParseDateWithOptions parses with the given options using the default parser
+func ParseDateWithOptions(dateStr string, opts ParseOptions) (time.Time, error) {
+	return Default().ParseWithOptions(dateStr, opts)
+}
+
+// ParseDateDetailed parses with the given options using the default parser
+// and reports how the date was read
+func ParseDateDetailed(dateStr string, opts ParseOptions) (Result, error) {
+	return Default().ParseDetailedWithOptions(dateStr, opts)
+}
+
+// ParseDateRange parses dateStr into the period it denotes using the
+// default parser
+func ParseDateRange(dateStr string, opts ParseOptions) (Interval, error) {
+	return Default().ParseRangeWithOptions(dateStr, opts)
 }
 
-// ParseWithLocation parses with a specific timezone using the singleton instance
+// ParseDateWithLocation parses with a specific timezone using the default parser
 func ParseDateWithLocation(dateStr string, loc *time.Location) (time.Time, error) {
-	return GetParser().ParseWithLocation(dateStr, loc)
+	return Default().ParseWithLocation(dateStr, loc)
 }
 
-// AddFormat adds a custom format to the singleton parser
+// AddFormat adds a custom format to the default parser, changing parsing for
+// every caller of the package-level functions.
+//
+// Deprecated: create a parser with NewDateTimeParser(WithExtraLayouts(format)) instead.
 func AddFormat(format string) {
-	GetParser().AddFormat(format)
+	Default().AddFormat(format)
 }
@@ -0,0 +1,163 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var benchmarkInputs = []struct {
+	name  string
+	input string
+}{
+	{"rfc3339", "2021-03-04T10:20:30+07:00"},
+	{"rfc3339_nano", "2021-03-04T10:20:30.123456789Z"},
+	{"iso_date", "2021-03-04"},
+	{"long_month", "January 2, 2021"},
+	{"datetime_pm", "Jan 2, 2021 3:04:05 PM"},
+	{"day_first", "13/04/2021"},
+	{"time_only", "15:04"},
+	{"fail_text", "not a date at all"},
+	{"fail_out_of_range", "2021-13-45"},
+	{"fail_numeric", "12345"},
+	{"natural", "3 days ago"},
+}
+
+func BenchmarkParse(b *testing.B) {
+	p := NewDateTimeParser()
+	for _, bm := range benchmarkInputs {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				p.Parse(bm.input)
+			}
+		})
+	}
+}
+
+// fullScan is the reference the layout matcher must agree with: every layout
+// tried in priority order
+func fullScan(layouts []string, value string) (time.Time, string, bool) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, layout, true
+		}
+	}
+	return time.Time{}, "", false
+}
+
+func TestLayoutSetMatchesFullScan(t *testing.T) {
+	layouts := append([]string{}, NewDateTimeParser().formats.Load().layouts...)
+	layouts = append(layouts, NewDateTimeParser().dayFirst.layouts...)
+	layouts = append(layouts, time.RFC1123, time.Stamp, "02.01.2006", "01.02.2006")
+	set := newLayoutSet(layouts)
+
+	var inputs []string
+	times := []time.Time{
+		time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC),
+		time.Date(1999, 12, 31, 23, 59, 59, 123_000_000, time.FixedZone("", -5*3600)),
+		time.Date(2024, 11, 13, 14, 0, 0, 500, time.FixedZone("WIB", 7*3600)),
+	}
+	for _, tm := range times {
+		for _, layout := range layouts {
+			inputs = append(inputs, tm.Format(layout), strings.ToLower(tm.Format(layout)))
+		}
+	}
+	inputs = append(inputs, "2021-03-04 05:06:07.5", "2021-13-45", "13/04/2021", "not a date", "", "1/2/2006  3:04:05 PM")
+
+	// Twice, so the second pass runs with the adaptive cache warmed up
+	for pass := 0; pass < 2; pass++ {
+		for _, input := range inputs {
+			wantTime, wantLayout, wantOK := fullScan(layouts, input)
+			gotTime, gotLayout, gotOK := set.match(input, time.Parse)
+			if gotOK != wantOK || !gotTime.Equal(wantTime) {
+				t.Fatalf("match(%q) = %s, %q, %v; full scan %s, %q, %v", input, gotTime, gotLayout, gotOK, wantTime, wantLayout, wantOK)
+			}
+			if gotOK && describeLayout(gotLayout, gotTime).Precision != describeLayout(wantLayout, wantTime).Precision {
+				t.Errorf("match(%q) used %q, full scan %q", input, gotLayout, wantLayout)
+			}
+		}
+	}
+}
+
+func TestFallbackHints(t *testing.T) {
+	// "3. 4. 2021" has no letters but normalization drops the ordinal dots
+	p := NewDateTimeParser(WithLocale("de"), WithExtraLayouts("2 1 2006"))
+	for _, input := range []string{
+		"2021", "3 days ago", "Q3 2021", "mid-1990s", "Mittwoch, 3. März 2021", "3. 4. 2021",
+		"15 Ramadan 1445 AH", "Rabi al-Awwal 1445", "1500-02-29 (O.S.)", "令和3年5月1日", "Shōwa 64", "H31.4.30",
+	} {
+		known, letters := fallbackHints(input)
+		if !known && (letters || !tidiedNumeric(input)) {
+			t.Errorf("%q would skip the fallbacks", input)
+		}
+		if _, err := p.Parse(input); err != nil {
+			t.Errorf("parse %q: %v", input, err)
+		}
+	}
+
+	for _, input := range []string{"not a date at all", "2021-13-45", "12345", "xyz 2021"} {
+		if known, letters := fallbackHints(input); known || !letters && tidiedNumeric(input) {
+			t.Errorf("%q would run the fallbacks", input)
+		}
+	}
+}
+
+// TestRFC3339HotPath guards the most common input: RFC 3339 is tried before
+// the input is classified, without allocating, and stays within a few times
+// the cost of time.Parse itself
+func TestRFC3339HotPath(t *testing.T) {
+	p := NewDateTimeParser()
+	const input = "2021-03-04T10:20:30+07:00"
+	if !p.formats.Load().looksFirst(input) {
+		t.Fatal("RFC 3339 input is not tried against the first layout")
+	}
+	if allocs := testing.AllocsPerRun(100, func() { p.Parse(input) }); allocs != 0 {
+		t.Errorf("parsing RFC 3339 allocates %.0f times", allocs)
+	}
+
+	if testing.Short() {
+		t.Skip("timing comparison skipped in short mode")
+	}
+	// The fastest of a few rounds, to ride out scheduling noise
+	timed := func(f func()) time.Duration {
+		best := time.Duration(1<<63 - 1)
+		for round := 0; round < 5; round++ {
+			start := time.Now()
+			for i := 0; i < 10000; i++ {
+				f()
+			}
+			best = min(best, time.Since(start))
+		}
+		return best
+	}
+	parse := timed(func() { p.Parse(input) })
+	reference := timed(func() { time.Parse(time.RFC3339, input) })
+	ratio := float64(parse) / float64(reference)
+	t.Logf("Parse %s, time.Parse %s per RFC 3339 input", parse/10000, reference/10000)
+	if ratio > 4 {
+		t.Errorf("Parse takes %s for RFC 3339, %.1f times time.Parse", parse/10000, ratio)
+	}
+}
+
+func BenchmarkFullScan(b *testing.B) {
+	layouts := NewDateTimeParser().formats.Load().layouts
+	for _, bm := range benchmarkInputs {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				fullScan(layouts, bm.input)
+			}
+		})
+	}
+}
+
+func BenchmarkLayoutMatch(b *testing.B) {
+	set := NewDateTimeParser().formats.Load()
+	for _, bm := range benchmarkInputs {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				set.match(bm.input, time.Parse)
+			}
+		})
+	}
+}
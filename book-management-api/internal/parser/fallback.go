@@ -0,0 +1,114 @@
+package parser
+
+import "strings"
+
+// baseVocabulary returns the words of which input the natural-language or
+// calendar fallbacks can read has at least one: natural-language keywords
+// ("q" and "s" come from "Q3 2021" and "1990s"), calendar markers, every
+// prefix of the Islamic month names, which are written in several words as
+// in "Rabi al-Awwal", and the Japanese era names and letters. RegisterLocale
+// adds the locale's words.
+func baseVocabulary() map[string]bool {
+	words := toSet([]string{
+		"now", "today", "yesterday", "tomorrow", "ago", "in", "later", "hence",
+		"last", "next", "this", "early", "mid", "late", "q", "s",
+	})
+	for marker := range julianMarkers {
+		words[marker] = true
+	}
+	for marker := range islamicMarkers {
+		words[marker] = true
+	}
+	for name := range islamicMonthNames {
+		for i := 1; i <= len(name); i++ {
+			words[name[:i]] = true
+		}
+	}
+	for _, era := range japaneseEras {
+		name := strings.ToLower(era.name)
+		for _, o := range []string{"o", "ō", "ô", "ou", "oo"} {
+			words[strings.ReplaceAll(name, "o", o)] = true
+		}
+		words[string(era.letter)] = true
+	}
+	return words
+}
+
+// fallbackHints scans the letter runs of dateStr, reporting whether any is a
+// word of the vocabulary, and whether there are letters at all
+func fallbackHints(dateStr string) (known, letters bool) {
+	vocabulary := registry.Load().vocabulary
+	var buf [32]byte
+	for i := 0; i < len(dateStr); {
+		if !isLetter(dateStr[i]) {
+			i++
+			continue
+		}
+		start := i
+		ascii := true
+		for i < len(dateStr) && isLetter(dateStr[i]) {
+			ascii = ascii && dateStr[i] < 0x80
+			i++
+		}
+		letters = true
+
+		word := dateStr[start:i]
+		switch {
+		case !ascii:
+			if isKnownWord(vocabulary, strings.ToLower(word)) {
+				return true, true
+			}
+		case len(word) <= len(buf):
+			lower := buf[:len(word)]
+			for j := range lower {
+				lower[j] = word[j] | 0x20
+			}
+			if vocabulary[string(lower)] {
+				return true, true
+			}
+		}
+	}
+	return false, letters
+}
+
+func isKnownWord(vocabulary map[string]bool, word string) bool {
+	if vocabulary[word] {
+		return true
+	}
+	for _, era := range japaneseEras {
+		if strings.HasPrefix(word, era.kanji) {
+			return true
+		}
+	}
+	return false
+}
+
+// tidiedNumeric reports whether locale normalization or the natural-language
+// year can read dateStr, which has no letters: it must be a bare year or
+// contain commas, repeated spaces or ordinal dots for normalization to tidy
+func tidiedNumeric(dateStr string) bool {
+	if len(dateStr) == 4 && strings.Trim(dateStr, "0123456789") == "" {
+		return true
+	}
+	for i := 0; i < len(dateStr); i++ {
+		switch c := dateStr[i]; {
+		case c == ',' || c == '\t':
+			return true
+		case c == ' ' && i > 0 && dateStr[i-1] == ' ':
+			return true
+		case c == '.' && i > 0 && isDigit(dateStr[i-1]) && (i+1 == len(dateStr) || dateStr[i+1] == ' ' || dateStr[i+1] == '\t'):
+			return true
+		}
+	}
+	return false
+}
+
+// builtinCalendar reports whether calendar is one of DefaultCalendars, which
+// all need a marker, month or era name; other calendars are always tried
+func builtinCalendar(calendar Calendar) bool {
+	switch calendar.(type) {
+	case JulianCalendar, IslamicCalendar, JapaneseCalendar:
+		return true
+	}
+	return false
+}
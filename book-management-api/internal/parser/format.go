@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// DateFormatParam is the query parameter selecting the output format per request
+const DateFormatParam = "date_format"
+
+// maxLayoutLength bounds custom layouts given per request
+const maxLayoutLength = 64
+
+// DateFormat is how times are written in responses: a Go reference layout or
+// a Unix timestamp. The zero value is FormatRFC3339.
+type DateFormat struct {
+	name   string
+	layout string
+	// unit is set for Unix timestamps, which are written as JSON numbers
+	unit UnixUnit
+}
+
+// Named output formats accepted by ParseDateFormat
+var (
+	// FormatRFC3339 writes fractional seconds only when they are not zero
+	FormatRFC3339    = DateFormat{name: "rfc3339", layout: time.RFC3339Nano}
+	FormatISODate    = DateFormat{name: "iso-date", layout: "2006-01-02"}
+	FormatUnix       = DateFormat{name: "unix", unit: UnixSeconds}
+	FormatUnixMillis = DateFormat{name: "unix_ms", unit: UnixMillis}
+	FormatUnixMicros = DateFormat{name: "unix_us", unit: UnixMicros}
+	FormatUnixNanos  = DateFormat{name: "unix_ns", unit: UnixNanos}
+)
+
+var dateFormats = []DateFormat{
+	FormatRFC3339, FormatISODate,
+	FormatUnix, FormatUnixMillis, FormatUnixMicros, FormatUnixNanos,
+}
+
+// ParseDateFormat reads a named format ("rfc3339", "iso-date", "unix",
+// "unix_ms", "unix_us" or "unix_ns") or a Go reference layout such as
+// "02 Jan 2006"; an empty value is FormatRFC3339
+func ParseDateFormat(value string) (DateFormat, error) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	if name == "" {
+		return FormatRFC3339, nil
+	}
+	for _, f := range dateFormats {
+		if f.name == name {
+			return f, nil
+		}
+	}
+
+	// A layout must contain at least one element of the reference time
+	reference := time.Date(2001, time.March, 4, 5, 6, 7, 0, time.UTC)
+	if len(value) > maxLayoutLength || reference.Format(value) == value {
+		return FormatRFC3339, fmt.Errorf("invalid date format %q, expected rfc3339, iso-date, unix, unix_ms or a layout such as 2006-01-02", value)
+	}
+	return DateFormat{layout: value}, nil
+}
+
+// WithParam returns the format selected by a date_format query parameter
+// value, or f when the value is empty
+func (f DateFormat) WithParam(value string) (DateFormat, error) {
+	if strings.TrimSpace(value) == "" {
+		return f, nil
+	}
+	return ParseDateFormat(value)
+}
+
+// String returns the name or layout accepted by ParseDateFormat
+func (f DateFormat) String() string {
+	if f.name == "" && f.layout == "" {
+		return FormatRFC3339.name
+	}
+	if f.name != "" {
+		return f.name
+	}
+	return f.layout
+}
+
+// IsUnix reports whether the format writes Unix timestamps
+func (f DateFormat) IsUnix() bool {
+	return f.unit != UnixAuto
+}
+
+// Format writes t in the format; layouts keep the zone of t
+func (f DateFormat) Format(t time.Time) string {
+	if f.IsUnix() {
+		return unixValue(t, f.unit).String()
+	}
+	if f.layout == "" {
+		return t.Format(FormatRFC3339.layout)
+	}
+	return t.Format(f.layout)
+}
+
+// unixValue is t as a whole number of units, rounded down; it is not bound
+// to the range of int64, so nanoseconds work for any date
+func unixValue(t time.Time, unit UnixUnit) *big.Int {
+	u := unixUnits[unit-UnixSeconds]
+	value := new(big.Int).Mul(big.NewInt(t.Unix()), big.NewInt(int64(time.Second)/u.perUnit))
+	return value.Add(value, big.NewInt(int64(t.Nanosecond())/u.perUnit))
+}
+
+// FormattedTime is a time written to JSON in the given format: a number for
+// Unix timestamps and a string otherwise
+type FormattedTime struct {
+	Time   time.Time
+	Format DateFormat
+}
+
+// MarshalJSON implements json.Marshaler
+func (t FormattedTime) MarshalJSON() ([]byte, error) {
+	if t.Format.IsUnix() {
+		return []byte(t.Format.Format(t.Time)), nil
+	}
+	return json.Marshal(t.Format.Format(t.Time))
+}
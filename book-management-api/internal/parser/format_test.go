@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDateFormatRoundTrip(t *testing.T) {
+	want := time.Date(2021, time.May, 1, 0, 0, 0, 0, time.UTC)
+	layouts := []string{"rfc3339", "iso-date", "unix", "unix_ms", "unix_us", "unix_ns", "02 Jan 2006", "January 2, 2006"}
+
+	p := NewDateTimeParser()
+	for _, layout := range layouts {
+		format, err := ParseDateFormat(layout)
+		if err != nil {
+			t.Fatalf("ParseDateFormat(%q): %v", layout, err)
+		}
+		formatted := format.Format(want)
+		got, err := p.Parse(formatted)
+		if err != nil {
+			t.Errorf("%s: parse %q: %v", layout, formatted, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("%s: parse %q = %s, want %s", layout, formatted, got, want)
+		}
+	}
+}
+
+func TestFormattedTimeJSON(t *testing.T) {
+	tests := []struct {
+		format string
+		time   time.Time
+		want   string
+	}{
+		{"", time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC), `"2021-05-01T00:00:00Z"`},
+		{"rfc3339", time.Date(2021, 5, 1, 12, 30, 0, 250000000, time.FixedZone("", 7*3600)), `"2021-05-01T12:30:00.25+07:00"`},
+		{"iso-date", time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC), `"2021-05-01"`},
+		{"unix", time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC), `1619827200`},
+		{"unix_ms", time.Date(2021, 5, 1, 0, 0, 0, 123000000, time.UTC), `1619827200123`},
+		// Units are rounded down, before 1970 as after
+		{"unix", time.Date(1969, 12, 31, 23, 59, 59, 500000000, time.UTC), `-1`},
+		{"unix_ms", time.Date(1969, 12, 31, 23, 59, 59, 500000000, time.UTC), `-500`},
+		// Nanoseconds past the range of int64
+		{"unix_ns", time.Date(1582, 10, 15, 0, 0, 0, 0, time.UTC), `-12219292800000000000`},
+		{"Mon, 02 Jan 2006", time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC), `"Sat, 01 May 2021"`},
+	}
+	for _, tt := range tests {
+		format, err := ParseDateFormat(tt.format)
+		if err != nil {
+			t.Fatalf("ParseDateFormat(%q): %v", tt.format, err)
+		}
+		got, err := json.Marshal(FormattedTime{Time: tt.time, Format: format})
+		if err != nil {
+			t.Fatalf("%q: marshal: %v", tt.format, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%q: marshal %s = %s, want %s", tt.format, tt.time, got, tt.want)
+		}
+	}
+
+	// The zero format is RFC 3339
+	got, _ := json.Marshal(FormattedTime{Time: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)})
+	if string(got) != `"2021-05-01T00:00:00Z"` {
+		t.Errorf("zero format: marshal = %s", got)
+	}
+}
+
+func TestParseDateFormat(t *testing.T) {
+	for _, value := range []string{"ISO-DATE", " Unix_MS ", "2006-01-02T15:04"} {
+		if _, err := ParseDateFormat(value); err != nil {
+			t.Errorf("ParseDateFormat(%q): %v", value, err)
+		}
+	}
+	for _, value := range []string{"yyyy-mm-dd", "%Y-%m-%d", "unix_s", "epoch"} {
+		if _, err := ParseDateFormat(value); err == nil {
+			t.Errorf("ParseDateFormat(%q) succeeded, want an error", value)
+		}
+	}
+
+	format, err := FormatISODate.WithParam("")
+	if err != nil || format != FormatISODate {
+		t.Errorf("WithParam(\"\") = %v, %v, want %v", format, err, FormatISODate)
+	}
+	format, err = FormatISODate.WithParam("unix")
+	if err != nil || format != FormatUnix {
+		t.Errorf("WithParam(\"unix\") = %v, %v, want %v", format, err, FormatUnix)
+	}
+}
@@ -0,0 +1,270 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interval is the half-open period [Start, End) a date expression denotes.
+// Start equals End for an instant.
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// IsInstant reports whether the interval is a single point in time
+func (i Interval) IsInstant() bool {
+	return i.Start.Equal(i.End)
+}
+
+// Contains reports whether t falls within the interval; an instant contains
+// only itself
+func (i Interval) Contains(t time.Time) bool {
+	if i.IsInstant() {
+		return t.Equal(i.Start)
+	}
+	return !t.Before(i.Start) && t.Before(i.End)
+}
+
+// String formats the interval as an ISO 8601 start/end pair
+func (i Interval) String() string {
+	return i.Start.Format(time.RFC3339Nano) + "/" + i.End.Format(time.RFC3339Nano)
+}
+
+// Duration is an ISO 8601 duration such as P1Y2M3DT4H. Years, months and
+// days are calendar units, so their length depends on the date they are
+// added to.
+type Duration struct {
+	Years, Months, Days int
+	Time                time.Duration
+}
+
+var isoDuration = regexp.MustCompile(`^([-+])?P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+(?:[.,]\d+)?)H)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+
+// ParseDuration reads an ISO 8601 duration such as P1Y2M3DT4H, P2W or
+// PT1.5S; a leading minus negates it
+func ParseDuration(value string) (Duration, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	m := isoDuration.FindStringSubmatch(s)
+	if m == nil || strings.HasSuffix(s, "P") || strings.HasSuffix(s, "T") {
+		return Duration{}, fmt.Errorf("invalid ISO 8601 duration %q, expected e.g. P1Y2M3DT4H", value)
+	}
+
+	var d Duration
+	d.Years, _ = strconv.Atoi(m[2])
+	d.Months, _ = strconv.Atoi(m[3])
+	weeks, _ := strconv.Atoi(m[4])
+	d.Days, _ = strconv.Atoi(m[5])
+	d.Days += 7 * weeks
+	for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second} {
+		if m[6+i] == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(strings.Replace(m[6+i], ",", ".", 1), 64)
+		if err != nil {
+			return Duration{}, fmt.Errorf("invalid ISO 8601 duration %q: %v", value, err)
+		}
+		d.Time += time.Duration(n * float64(unit))
+	}
+
+	if m[1] == "-" {
+		d = d.negate()
+	}
+	return d, nil
+}
+
+func (d Duration) negate() Duration {
+	return Duration{Years: -d.Years, Months: -d.Months, Days: -d.Days, Time: -d.Time}
+}
+
+// AddTo returns t moved by the duration
+func (d Duration) AddTo(t time.Time) time.Time {
+	return t.AddDate(d.Years, d.Months, d.Days).Add(d.Time)
+}
+
+// IsZero reports whether the duration is empty
+func (d Duration) IsZero() bool {
+	return d == Duration{}
+}
+
+// String formats the duration in ISO 8601
+func (d Duration) String() string {
+	if d.IsZero() {
+		return "PT0S"
+	}
+	sign := ""
+	if d.Years < 0 || d.Months < 0 || d.Days < 0 || d.Time < 0 {
+		sign, d = "-", d.negate()
+	}
+
+	var b strings.Builder
+	b.WriteString(sign + "P")
+	for _, part := range []struct {
+		n    int
+		unit string
+	}{{d.Years, "Y"}, {d.Months, "M"}, {d.Days, "D"}} {
+		if part.n != 0 {
+			fmt.Fprintf(&b, "%d%s", part.n, part.unit)
+		}
+	}
+	if d.Time != 0 {
+		b.WriteString("T")
+		hours := d.Time / time.Hour
+		minutes := (d.Time % time.Hour) / time.Minute
+		seconds := d.Time % time.Minute
+		if hours != 0 {
+			fmt.Fprintf(&b, "%dH", hours)
+		}
+		if minutes != 0 {
+			fmt.Fprintf(&b, "%dM", minutes)
+		}
+		if seconds != 0 {
+			b.WriteString(strconv.FormatFloat(seconds.Seconds(), 'f', -1, 64) + "S")
+		}
+	}
+	return b.String()
+}
+
+var (
+	// yearRange matches "1990-1995" and "1990 – 1995"
+	yearRange = regexp.MustCompile(`^(\d{4})\s*[-–—]\s*(\d{4})$`)
+	// rangeSeparator splits human ranges: "Jan–Mar 2021", "2020 to 2021",
+	// "March 3 - May 5, 2021"
+	rangeSeparator = regexp.MustCompile(`\s*[–—]\s*|\s+-\s+|\s+(?:to|until|through|thru)\s+`)
+	// trailingYear is borrowed by the start of "Jan–Mar 2021"
+	trailingYear = regexp.MustCompile(`(?:,\s*|\s+)(\d{4})$`)
+)
+
+// ParseInterval parses a period:
+//   - ISO 8601 intervals: 2020-01-01/2020-12-31, 2020-01-01/P1M or P1M/2020-12-31
+//   - human ranges: 1990-1995, Jan–Mar 2021, 2020 to 2021
+//   - any single date or period accepted by ParseRange, such as Q3 2021
+//
+// Ends given as dates include the whole day (or month, or year), so
+// 2020-01-01/2020-12-31 covers all of 2020; ends with a time are exclusive.
+func (p *DateTimeParser) ParseInterval(value string) (Interval, error) {
+	return p.ParseIntervalWithOptions(value, p.Options())
+}
+
+// ParseIntervalWithOptions is ParseInterval with the given options instead
+// of the parser's own
+func (p *DateTimeParser) ParseIntervalWithOptions(value string, opts ParseOptions) (Interval, error) {
+	value = strings.TrimSpace(value)
+
+	if start, end, ok := strings.Cut(value, "/"); ok && !strings.Contains(end, "/") {
+		interval, err := p.parseISOInterval(value, strings.TrimSpace(start), strings.TrimSpace(end), opts)
+		if err == nil {
+			return interval, nil
+		}
+		// Not an interval after all if the whole is a date, such as 3/2021 in a custom layout
+		if whole, wholeErr := p.ParseRangeWithOptions(value, opts); wholeErr == nil {
+			return whole, nil
+		}
+		return Interval{}, err
+	}
+
+	if m := yearRange.FindStringSubmatch(value); m != nil {
+		return p.parseRangeParts(value, m[1], m[2], opts)
+	}
+	if parts := rangeSeparator.Split(value, -1); len(parts) == 2 {
+		start, end := parts[0], parts[1]
+		// "Jan–Mar 2021" and "March 3 - May 5, 2021": the start takes the end's year
+		if _, err := p.ParseRangeWithOptions(start, opts); err != nil {
+			if m := trailingYear.FindStringSubmatch(end); m != nil {
+				if strings.HasPrefix(m[0], ",") {
+					start += ","
+				}
+				start += " " + m[1]
+			}
+		}
+		return p.parseRangeParts(value, start, end, opts)
+	}
+
+	return p.ParseRangeWithOptions(value, opts)
+}
+
+// parseISOInterval reads start/end, start/duration and duration/end
+func (p *DateTimeParser) parseISOInterval(value, start, end string, opts ParseOptions) (Interval, error) {
+	switch {
+	case isDuration(start) && isDuration(end):
+		return Interval{}, fmt.Errorf("invalid interval %q: both sides are durations", value)
+	case isDuration(end):
+		d, err := ParseDuration(end)
+		if err != nil {
+			return Interval{}, err
+		}
+		from, err := p.ParseDetailedWithOptions(start, opts)
+		if err != nil {
+			return Interval{}, err
+		}
+		return checkedInterval(value, from.Time, d.AddTo(from.Time), isPeriod(from))
+	case isDuration(start):
+		d, err := ParseDuration(start)
+		if err != nil {
+			return Interval{}, err
+		}
+		until, period, err := p.rangeEnd(end, opts)
+		if err != nil {
+			return Interval{}, err
+		}
+		return checkedInterval(value, d.negate().AddTo(until), until, period)
+	}
+	return p.parseRangeParts(value, start, end, opts)
+}
+
+// parseRangeParts joins the start of one period to the end of another
+func (p *DateTimeParser) parseRangeParts(value, start, end string, opts ParseOptions) (Interval, error) {
+	from, err := p.ParseDetailedWithOptions(start, opts)
+	if err != nil {
+		return Interval{}, err
+	}
+	until, period, err := p.rangeEnd(end, opts)
+	if err != nil {
+		return Interval{}, err
+	}
+	return checkedInterval(value, from.Time, until, period || isPeriod(from))
+}
+
+// rangeEnd returns where a range ending at end stops: after the whole day,
+// month or year for dates and periods, at the instant itself for times.
+// period reports which of the two end was.
+func (p *DateTimeParser) rangeEnd(end string, opts ParseOptions) (until time.Time, period bool, err error) {
+	result, err := p.ParseDetailedWithOptions(end, opts)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if !isPeriod(result) {
+		return result.Time, false, nil
+	}
+	return result.End, true, nil
+}
+
+// isPeriod reports whether result is a day or longer rather than an instant
+func isPeriod(result Result) bool {
+	return result.Precision < PrecisionHour
+}
+
+// checkedInterval rejects an interval that ends before it starts, or that is
+// empty when a side is a period, as in "2020 to 2019"; an empty interval
+// between two instants is valid
+func checkedInterval(value string, start, end time.Time, period bool) (Interval, error) {
+	if end.Before(start) || period && !end.After(start) {
+		return Interval{}, fmt.Errorf("invalid interval %q: it ends before it starts", value)
+	}
+	return Interval{Start: start, End: end}, nil
+}
+
+// isDuration reports whether s is written as an ISO 8601 duration
+func isDuration(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	return s != "" && (s[0] == 'P' || s[0] == 'p')
+}
+
+// ParseDateInterval parses a period such as "2020-01-01/P1M" or
+// "1990-1995" using the default parser
+func ParseDateInterval(value string, opts ParseOptions) (Interval, error) {
+	return Default().ParseIntervalWithOptions(value, opts)
+}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Duration
+		text  string
+	}{
+		{"P1Y2M3DT4H", Duration{Years: 1, Months: 2, Days: 3, Time: 4 * time.Hour}, "P1Y2M3DT4H"},
+		{"P2W", Duration{Days: 14}, "P14D"},
+		{"PT1.5S", Duration{Time: 1500 * time.Millisecond}, "PT1.5S"},
+		{"PT90M", Duration{Time: 90 * time.Minute}, "PT1H30M"},
+		{"-P1D", Duration{Days: -1}, "-P1D"},
+		{"p1m", Duration{Months: 1}, "P1M"},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.input)
+		if err != nil {
+			t.Errorf("ParseDuration(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want || got.String() != tt.text {
+			t.Errorf("ParseDuration(%q) = %+v (%s), want %+v (%s)", tt.input, got, got, tt.want, tt.text)
+		}
+	}
+
+	for _, input := range []string{"", "P", "PT", "1Y", "P1H", "PT1D", "P1.5Y"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q) accepted an invalid duration", input)
+		}
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		input      string
+		start, end time.Time
+	}{
+		{"2020-01-01/2020-12-31", date(2020, 1, 1), date(2021, 1, 1)},
+		{"2020-01-01T10:00:00Z/2020-01-01T12:00:00Z", date(2020, 1, 1).Add(10 * time.Hour), date(2020, 1, 1).Add(12 * time.Hour)},
+		{"2020-01-31/P1M", date(2020, 1, 31), date(2020, 3, 2)},
+		{"2020-01-01/P1Y2M3DT4H", date(2020, 1, 1), date(2021, 3, 4).Add(4 * time.Hour)},
+		{"P1M/2020-12-31", date(2020, 12, 1), date(2021, 1, 1)},
+		{"1990-1995", date(1990, 1, 1), date(1996, 1, 1)},
+		{"1990 – 1995", date(1990, 1, 1), date(1996, 1, 1)},
+		{"Jan–Mar 2021", date(2021, 1, 1), date(2021, 4, 1)},
+		{"March 3 - May 5, 2021", date(2021, 3, 3), date(2021, 5, 6)},
+		{"2020 to Q1 2021", date(2020, 1, 1), date(2021, 4, 1)},
+		{"Q3 2021", date(2021, 7, 1), date(2021, 10, 1)},
+		{"2021/03/04", date(2021, 3, 4), date(2021, 3, 5)},
+		{"2020-01-01T10:00:00Z/2020-01-01T10:00:00Z", date(2020, 1, 1).Add(10 * time.Hour), date(2020, 1, 1).Add(10 * time.Hour)},
+	}
+
+	p := NewDateTimeParser()
+	for _, tt := range tests {
+		got, err := p.ParseInterval(tt.input)
+		if err != nil {
+			t.Errorf("ParseInterval(%q): %v", tt.input, err)
+			continue
+		}
+		if !got.Start.Equal(tt.start) || !got.End.Equal(tt.end) {
+			t.Errorf("ParseInterval(%q) = %s, want %s", tt.input, got, Interval{tt.start, tt.end})
+		}
+	}
+
+	for _, input := range []string{"1995-1990", "2020 to 2019", "Q2 2021 to Q1 2021", "2020-01-02/2020-01-01", "P1M/P1D", "2020-01-01/nonsense", "2021-12-31/-P1D", "2020-01-01/P0D"} {
+		if _, err := p.ParseInterval(input); err == nil {
+			t.Errorf("ParseInterval(%q) accepted an invalid interval", input)
+		}
+	}
+}
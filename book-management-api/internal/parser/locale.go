@@ -0,0 +1,292 @@
+package parser
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Locale holds the month and weekday names, ordinal suffixes and filler words
+// of a language, used to read dates such as "1er mars 2021" or
+// "Senin, 5 Januari 2021"
+type Locale struct {
+	Tag string
+	// Months lists, per month, the display name followed by accepted
+	// abbreviations and alternative spellings
+	Months [12][]string
+	// Weekdays lists the names per weekday starting with Sunday, display name first
+	Weekdays [7][]string
+	// Ordinals are the suffixes that may follow a day number, as in "2nd" or "1er"
+	Ordinals []string
+	// Fillers are words skipped when reading a date, as in "5 de mayo de 2021"
+	Fillers []string
+	// LongLayout is the long date layout written with English names, which
+	// FormatLong replaces with the locale's
+	LongLayout string
+
+	months   map[string]time.Month
+	weekdays map[string]bool
+	ordinals map[string]bool
+	fillers  map[string]bool
+}
+
+// DefaultLocale is used when no locale is selected
+const DefaultLocale = "en"
+
+// localeRegistry holds the registered locales and the words the fallbacks
+// know. It is never changed once published: RegisterLocale replaces it, so
+// parsing reads it without locking.
+type localeRegistry struct {
+	locales    map[string]*Locale
+	vocabulary map[string]bool
+}
+
+var (
+	registry   atomic.Pointer[localeRegistry]
+	registerMu sync.Mutex
+)
+
+func init() {
+	registry.Store(&localeRegistry{locales: map[string]*Locale{}, vocabulary: baseVocabulary()})
+	for _, locale := range []*Locale{
+		{
+			Tag: "en",
+			Months: [12][]string{
+				{"January", "jan"}, {"February", "feb"}, {"March", "mar"}, {"April", "apr"},
+				{"May"}, {"June", "jun"}, {"July", "jul"}, {"August", "aug"},
+				{"September", "sep", "sept"}, {"October", "oct"}, {"November", "nov"}, {"December", "dec"},
+			},
+			Weekdays: [7][]string{
+				{"Sunday", "sun"}, {"Monday", "mon"}, {"Tuesday", "tue", "tues"}, {"Wednesday", "wed"},
+				{"Thursday", "thu", "thur", "thurs"}, {"Friday", "fri"}, {"Saturday", "sat"},
+			},
+			Ordinals:   []string{"st", "nd", "rd", "th"},
+			Fillers:    []string{"the", "of", "on"},
+			LongLayout: "Monday, January 2, 2006",
+		},
+		{
+			Tag: "id",
+			Months: [12][]string{
+				{"Januari", "jan"}, {"Februari", "feb", "peb"}, {"Maret", "mar"}, {"April", "apr"},
+				{"Mei"}, {"Juni", "jun"}, {"Juli", "jul"}, {"Agustus", "agu", "agt", "ags"},
+				{"September", "sep", "sept"}, {"Oktober", "okt"}, {"November", "nov", "nop"}, {"Desember", "des"},
+			},
+			Weekdays: [7][]string{
+				{"Minggu", "min", "ahad"}, {"Senin", "sen"}, {"Selasa", "sel"}, {"Rabu", "rab"},
+				{"Kamis", "kam"}, {"Jumat", "jum'at", "jum"}, {"Sabtu", "sab"},
+			},
+			Fillers:    []string{"tanggal", "tgl"},
+			LongLayout: "Monday, 2 January 2006",
+		},
+		{
+			Tag: "fr",
+			Months: [12][]string{
+				{"janvier", "janv"}, {"février", "fevrier", "févr", "fevr", "fév", "fev"}, {"mars"}, {"avril", "avr"},
+				{"mai"}, {"juin"}, {"juillet", "juil"}, {"août", "aout"},
+				{"septembre", "sept"}, {"octobre", "oct"}, {"novembre", "nov"}, {"décembre", "decembre", "déc", "dec"},
+			},
+			Weekdays: [7][]string{
+				{"dimanche", "dim"}, {"lundi", "lun"}, {"mardi", "mar"}, {"mercredi", "mer"},
+				{"jeudi", "jeu"}, {"vendredi", "ven"}, {"samedi", "sam"},
+			},
+			Ordinals:   []string{"er", "re", "e", "ème", "eme"},
+			Fillers:    []string{"le"},
+			LongLayout: "Monday 2 January 2006",
+		},
+		{
+			Tag: "de",
+			Months: [12][]string{
+				{"Januar", "jan", "jänner", "jaenner"}, {"Februar", "feb"}, {"März", "maerz", "mär", "mrz"}, {"April", "apr"},
+				{"Mai"}, {"Juni", "jun"}, {"Juli", "jul"}, {"August", "aug"},
+				{"September", "sep", "sept"}, {"Oktober", "okt"}, {"November", "nov"}, {"Dezember", "dez"},
+			},
+			Weekdays: [7][]string{
+				{"Sonntag", "so"}, {"Montag", "mo"}, {"Dienstag", "di"}, {"Mittwoch", "mi"},
+				{"Donnerstag", "do"}, {"Freitag", "fr"}, {"Samstag", "sa", "sonnabend"},
+			},
+			Ordinals:   []string{"."},
+			Fillers:    []string{"den", "am"},
+			LongLayout: "Monday, 2. January 2006",
+		},
+		{
+			Tag: "es",
+			Months: [12][]string{
+				{"enero", "ene"}, {"febrero", "feb"}, {"marzo", "mar"}, {"abril", "abr"},
+				{"mayo", "may"}, {"junio", "jun"}, {"julio", "jul"}, {"agosto", "ago"},
+				{"septiembre", "setiembre", "sep", "sept", "set"}, {"octubre", "oct"}, {"noviembre", "nov"}, {"diciembre", "dic"},
+			},
+			Weekdays: [7][]string{
+				{"domingo", "dom"}, {"lunes", "lun"}, {"martes"}, {"miércoles", "miercoles", "mié", "mie"},
+				{"jueves", "jue"}, {"viernes", "vie"}, {"sábado", "sabado", "sáb", "sab"},
+			},
+			Ordinals:   []string{"º", "°", "ª", "o", "ro"},
+			Fillers:    []string{"de", "del", "el"},
+			LongLayout: "Monday, 2 de January de 2006",
+		},
+	} {
+		RegisterLocale(locale)
+	}
+}
+
+// RegisterLocale adds or replaces a locale pack. It may be called while
+// other goroutines parse; they see the locale from their next parse. The
+// pack is copied, so later changes to locale have no effect.
+func RegisterLocale(locale *Locale) {
+	copied := *locale
+	locale = &copied
+	locale.months = make(map[string]time.Month)
+	for i, names := range locale.Months {
+		for _, name := range names {
+			locale.months[strings.ToLower(name)] = time.Month(i + 1)
+		}
+	}
+	locale.weekdays = make(map[string]bool)
+	for _, names := range locale.Weekdays {
+		for _, name := range names {
+			locale.weekdays[strings.ToLower(name)] = true
+		}
+	}
+	locale.ordinals = toSet(locale.Ordinals)
+	locale.fillers = toSet(locale.Fillers)
+
+	registerMu.Lock()
+	defer registerMu.Unlock()
+	current := registry.Load()
+	next := &localeRegistry{
+		locales:    make(map[string]*Locale, len(current.locales)+1),
+		vocabulary: make(map[string]bool, len(current.vocabulary)),
+	}
+	for tag, registered := range current.locales {
+		next.locales[tag] = registered
+	}
+	next.locales[locale.Tag] = locale
+	// Words of a replaced pack stay, as the vocabulary is only a hint
+	for word := range current.vocabulary {
+		next.vocabulary[word] = true
+	}
+	for _, words := range []map[string]bool{locale.weekdays, locale.ordinals, locale.fillers} {
+		for word := range words {
+			next.vocabulary[word] = true
+		}
+	}
+	for name := range locale.months {
+		next.vocabulary[name] = true
+	}
+	registry.Store(next)
+}
+
+func toSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[strings.ToLower(word)] = true
+	}
+	return set
+}
+
+// LookupLocale returns the locale pack for a language tag such as "fr" or
+// "fr-CA"; only the primary language is used
+func LookupLocale(tag string) (*Locale, bool) {
+	primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	primary, _, _ = strings.Cut(primary, "_")
+	locale, ok := registry.Load().locales[primary]
+	return locale, ok
+}
+
+// LocaleTags lists the supported locales
+func LocaleTags() []string {
+	locales := registry.Load().locales
+	tags := make([]string, 0, len(locales))
+	for tag := range locales {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// LocaleFromAcceptLanguage picks the supported locale the client prefers in
+// an Accept-Language header, or "" when none is supported
+func LocaleFromAcceptLanguage(header string) string {
+	type choice struct {
+		tag string
+		q   float64
+	}
+
+	var choices []choice
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		if tag != "" && q > 0 {
+			choices = append(choices, choice{tag, q})
+		}
+	}
+	sort.SliceStable(choices, func(i, j int) bool { return choices[i].q > choices[j].q })
+
+	for _, c := range choices {
+		if locale, ok := LookupLocale(c.tag); ok {
+			return locale.Tag
+		}
+	}
+	return ""
+}
+
+// dateToken matches words (with an optional abbreviation dot) and numbers
+// with an optional ordinal suffix; a trailing dot only counts as an ordinal
+// when followed by a space, so 15.04.2021 is left alone
+var dateToken = regexp.MustCompile(`[\p{L}'’]+\.?|\d+(?:[\p{L}º°ª]+|\.(?:\s|$))?`)
+
+// normalize rewrites a localized date in English: month names become English
+// month names, weekday names and filler words are dropped and ordinal
+// suffixes removed, so "Senin, 5 Januari 2021" reads "5 January 2021"
+func (l *Locale) normalize(dateStr string) string {
+	replaced := dateToken.ReplaceAllStringFunc(strings.ToLower(dateStr), func(token string) string {
+		if token[0] >= '0' && token[0] <= '9' {
+			digits := strings.TrimRightFunc(token, func(r rune) bool { return r < '0' || r > '9' })
+			suffix := strings.TrimSpace(token[len(digits):])
+			trailing := token[len(digits)+len(strings.TrimRight(token[len(digits):], " \t")):]
+			if suffix == "" || l.ordinals[suffix] {
+				return digits + trailing
+			}
+			return token
+		}
+
+		word := strings.TrimSuffix(token, ".")
+		if month, ok := l.months[word]; ok {
+			return month.String()
+		}
+		if l.weekdays[word] || l.fillers[word] {
+			return ""
+		}
+		return token
+	})
+
+	// Tidy the separators left behind by dropped words
+	fields := strings.Fields(strings.ReplaceAll(replaced, ",", " , "))
+	var out []string
+	for _, field := range fields {
+		if field == "," && (len(out) == 0 || out[len(out)-1] == ",") {
+			continue
+		}
+		out = append(out, field)
+	}
+	if len(out) > 0 && out[len(out)-1] == "," {
+		out = out[:len(out)-1]
+	}
+	return strings.ReplaceAll(strings.Join(out, " "), " ,", ",")
+}
+
+// FormatLong formats t as a long date with the locale's weekday and month
+// names, such as "lundi 5 janvier 2021"
+func (l *Locale) FormatLong(t time.Time) string {
+	formatted := t.Format(l.LongLayout)
+	formatted = strings.Replace(formatted, t.Weekday().String(), l.Weekdays[t.Weekday()][0], 1)
+	return strings.Replace(formatted, t.Month().String(), l.Months[t.Month()-1][0], 1)
+}
@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLocaleRoundTrip(t *testing.T) {
+	p := NewDateTimeParser()
+	for _, tag := range LocaleTags() {
+		locale, _ := LookupLocale(tag)
+		for month := time.January; month <= time.December; month++ {
+			for _, day := range []int{1, 2, 3, 15, 28} {
+				want := time.Date(2021, month, day, 0, 0, 0, 0, time.UTC)
+				formatted := locale.FormatLong(want)
+
+				got, err := p.ParseWithOptions(formatted, ParseOptions{Locale: tag})
+				if err != nil {
+					t.Fatalf("%s: parse %q: %v", tag, formatted, err)
+				}
+				if !got.Equal(want) {
+					t.Errorf("%s: parse %q = %s, want %s", tag, formatted, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestLocaleNames(t *testing.T) {
+	tests := []struct {
+		locale string
+		input  string
+		want   time.Time
+	}{
+		{"en", "January 1st, 2021", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"en", "Tuesday, March 2nd, 2021", time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"en", "Tuesday, March 2nd 2021", time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"en", "Mar 2 2021", time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"en", "March 2nd, 2021 15:04", time.Date(2021, 3, 2, 15, 4, 0, 0, time.UTC)},
+		{"en", "2 March 2021 15:04:05", time.Date(2021, 3, 2, 15, 4, 5, 0, time.UTC)},
+		{"en", "the 23rd of April 2021", time.Date(2021, 4, 23, 0, 0, 0, 0, time.UTC)},
+		{"id", "Senin, 17 Agustus 1945", time.Date(1945, 8, 17, 0, 0, 0, 0, time.UTC)},
+		{"id", "tanggal 5 Mei 2021", time.Date(2021, 5, 5, 0, 0, 0, 0, time.UTC)},
+		{"id", "Desember 2020", time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{"fr", "1er mars 2021", time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"fr", "le 14 juil. 1789", time.Date(1789, 7, 14, 0, 0, 0, 0, time.UTC)},
+		{"fr", "2 mars 2021 15:04:05", time.Date(2021, 3, 2, 15, 4, 5, 0, time.UTC)},
+		{"fr", "mardi 2 mars 2021 15:04", time.Date(2021, 3, 2, 15, 4, 0, 0, time.UTC)},
+		{"de", "Mittwoch, 3. März 2021", time.Date(2021, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{"de", "9. Nov. 1989", time.Date(1989, 11, 9, 0, 0, 0, 0, time.UTC)},
+		{"de", "3. März 2021 15:04", time.Date(2021, 3, 3, 15, 4, 0, 0, time.UTC)},
+		{"es", "5 de mayo de 2021", time.Date(2021, 5, 5, 0, 0, 0, 0, time.UTC)},
+		{"es", "1º de enero de 2000", time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"es", "5 de mayo de 2021 15:04:05", time.Date(2021, 5, 5, 15, 4, 5, 0, time.UTC)},
+		{"id", "Senin, 17 Agustus 1945 10:00", time.Date(1945, 8, 17, 10, 0, 0, 0, time.UTC)},
+		// English is understood whatever the locale
+		{"fr", "March 3rd, 2021", time.Date(2021, 3, 3, 0, 0, 0, 0, time.UTC)},
+	}
+
+	p := NewDateTimeParser()
+	for _, tt := range tests {
+		got, err := p.ParseWithOptions(tt.input, ParseOptions{Locale: tt.locale})
+		if err != nil {
+			t.Errorf("%s: parse %q: %v", tt.locale, tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: parse %q = %s, want %s", tt.locale, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestLocaleFromAcceptLanguage(t *testing.T) {
+	tests := map[string]string{
+		"":                              "",
+		"fr-CA":                         "fr",
+		"de-DE,de;q=0.9,en;q=0.8":       "de",
+		"ja;q=0.9,es;q=0.8":             "es",
+		"en;q=0.5, id-ID":               "id",
+		"pt-BR, *;q=0.1":                "",
+		"fr;q=0, en":                    "en",
+		"zh-Hant-TW;q=0.9, de_AT;q=0.4": "de",
+	}
+	for header, want := range tests {
+		if got := LocaleFromAcceptLanguage(header); got != want {
+			t.Errorf("LocaleFromAcceptLanguage(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestRegisterLocaleWhileParsing(t *testing.T) {
+	p := NewDateTimeParser()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				p.ParseWithOptions("3 mars 2021", ParseOptions{Locale: "fr"})
+			}
+		}()
+	}
+	RegisterLocale(&Locale{
+		Tag: "it",
+		Months: [12][]string{
+			{"gennaio", "gen"}, {"febbraio", "feb"}, {"marzo", "mar"}, {"aprile", "apr"},
+			{"maggio", "mag"}, {"giugno", "giu"}, {"luglio", "lug"}, {"agosto", "ago"},
+			{"settembre", "set"}, {"ottobre", "ott"}, {"novembre", "nov"}, {"dicembre", "dic"},
+		},
+		Weekdays: [7][]string{
+			{"domenica", "dom"}, {"lunedì", "lun"}, {"martedì", "mar"}, {"mercoledì", "mer"},
+			{"giovedì", "gio"}, {"venerdì", "ven"}, {"sabato", "sab"},
+		},
+		Ordinals:   []string{"º"},
+		Fillers:    []string{"il"},
+		LongLayout: "Monday 2 January 2006",
+	})
+	wg.Wait()
+
+	got, err := p.ParseWithOptions("3 marzo 2021", ParseOptions{Locale: "it"})
+	if want := time.Date(2021, 3, 3, 0, 0, 0, 0, time.UTC); err != nil || !got.Equal(want) {
+		t.Errorf("parse %q = %s, %v, want %s", "3 marzo 2021", got, err, want)
+	}
+}
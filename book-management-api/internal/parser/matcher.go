@@ -0,0 +1,224 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// shapeZones are the zones the probe time is formatted in to learn the shapes
+// a layout accepts, covering Z, +hh:mm, -hh:mm and named zones
+var shapeZones = []*time.Location{
+	time.UTC,
+	time.FixedZone("WIB", 7*3600),
+	time.FixedZone("", 7*3600),
+	time.FixedZone("", -5*3600),
+}
+
+// appendShape appends the token classes of a date string to shape: digit
+// runs become n, letter runs a, whitespace runs a single space, and other
+// characters stay as they are, so "Jan 2, 2021 3:04 PM" becomes
+// "a n, n n:n a". Fractional seconds are dropped because time.Parse accepts
+// them after any seconds field.
+func appendShape(shape []byte, s string) []byte {
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case isDigit(c):
+			for i < len(s) && isDigit(s[i]) {
+				i++
+			}
+			shape = append(shape, 'n')
+		case isLetter(c):
+			for i < len(s) && isLetter(s[i]) {
+				i++
+			}
+			shape = append(shape, 'a')
+		case c == ' ' || c == '\t':
+			for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+				i++
+			}
+			shape = append(shape, ' ')
+		case (c == '.' || c == ',') && i+1 < len(s) && isDigit(s[i+1]) && bytes.HasSuffix(shape, []byte(":n:n")):
+			for i++; i < len(s) && isDigit(s[i]); i++ {
+			}
+		default:
+			shape = append(shape, c)
+			i++
+		}
+	}
+	return shape
+}
+
+// charClass returns the token class of c as appendShape writes it
+func charClass(c byte) byte {
+	switch {
+	case isDigit(c):
+		return 'n'
+	case isLetter(c):
+		return 'a'
+	}
+	return c
+}
+
+// looksFirst reports whether value may be in the first layout
+func (s *layoutSet) looksFirst(value string) bool {
+	return len(s.layouts) > 0 && len(value) >= s.firstMinLen && charClass(value[0]) == s.firstClass
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isLetter treats every non-ASCII byte as a letter so accented names stay one run
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// layoutSet is an immutable list of layouts in priority order, grouped by the
+// shapes of input each layout can accept so that an input is only tried
+// against the few layouts of its shape. AddFormat replaces the set rather than
+// changing it, so parsing never copies the list under a lock.
+type layoutSet struct {
+	layouts []string
+	groups  map[string]*layoutGroup
+	// fallback holds the layouts that cannot be classified, tried for input
+	// of any other shape: named zones parse loosely and space-padded fields
+	// change shape
+	fallback *layoutGroup
+	// The first layout wins whenever it accepts the input, so input that
+	// looks like its output, at least firstMinLen long and starting with the
+	// same class of character, is tried against it before being classified.
+	// A wrong guess only costs a failed parse.
+	firstTraits layoutTraits
+	firstMinLen int
+	firstClass  byte
+}
+
+// layoutGroup holds the layouts that may accept one input shape
+type layoutGroup struct {
+	layouts []string
+	// adaptive is set when no two layouts read the same input differently,
+	// so the one that matched last can be tried first
+	adaptive bool
+	recent   atomic.Int32
+}
+
+func newLayoutSet(layouts []string) *layoutSet {
+	shapes := make([]map[string]bool, len(layouts))
+	var order []string
+	for i, layout := range layouts {
+		if strings.Contains(layout, "MST") || strings.Contains(layout, "_") {
+			continue
+		}
+		shapes[i] = make(map[string]bool)
+		for _, loc := range shapeZones {
+			shape := string(appendShape(nil, probe.In(loc).Format(layout)))
+			if !shapes[i][shape] {
+				shapes[i][shape] = true
+				order = append(order, shape)
+			}
+		}
+	}
+
+	// group collects, in priority order, the layouts accepting shape and
+	// those accepting anything
+	group := func(shape string) *layoutGroup {
+		g := &layoutGroup{}
+		for i, layout := range layouts {
+			if shapes[i] == nil || shapes[i][shape] {
+				g.layouts = append(g.layouts, layout)
+			}
+		}
+		g.adaptive = interchangeable(g.layouts)
+		return g
+	}
+
+	set := &layoutSet{layouts: layouts, groups: make(map[string]*layoutGroup), fallback: group("")}
+	if len(layouts) > 0 {
+		set.firstTraits = traitsOf(layouts[0])
+		for i, loc := range shapeZones {
+			formatted := probe.In(loc).Format(layouts[0])
+			if i == 0 || len(formatted) < set.firstMinLen {
+				set.firstMinLen = len(formatted)
+			}
+			if formatted != "" {
+				set.firstClass = charClass(formatted[0])
+			}
+		}
+	}
+	for _, shape := range order {
+		if set.groups[shape] == nil {
+			set.groups[shape] = group(shape)
+		}
+	}
+	return set
+}
+
+// with returns a new set with layout appended
+func (s *layoutSet) with(layout string) *layoutSet {
+	layouts := make([]string, len(s.layouts), len(s.layouts)+1)
+	copy(layouts, s.layouts)
+	return newLayoutSet(append(layouts, layout))
+}
+
+// interchangeable reports whether every layout reads the others' output as
+// the same time with the same precision and offset handling, such as
+// "Jan 2, 2006" and "January 2, 2006", but not "01/02/2006" and "02/01/2006"
+func interchangeable(layouts []string) bool {
+	for _, a := range layouts {
+		for _, b := range layouts {
+			if a == b {
+				continue
+			}
+			da, db := describeLayout(a, time.Time{}), describeLayout(b, time.Time{})
+			if da.Precision != db.Precision || da.ExplicitOffset != db.ExplicitOffset || da.TimeOnly != db.TimeOnly {
+				return false
+			}
+			for _, loc := range shapeZones {
+				want, err := time.Parse(a, probe.In(loc).Format(a))
+				if err != nil {
+					return false
+				}
+				if got, err := time.Parse(b, probe.In(loc).Format(a)); err == nil && !got.Equal(want) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// match parses value with the layouts of its shape in priority order, trying
+// the most recently successful layout first when that cannot change the result
+func (s *layoutSet) match(value string, parse func(layout, value string) (time.Time, error)) (time.Time, string, bool) {
+	var buf [64]byte
+	g, ok := s.groups[string(appendShape(buf[:0], value))]
+	if !ok {
+		g = s.fallback
+	}
+	if len(g.layouts) == 0 {
+		return time.Time{}, "", false
+	}
+
+	first := 0
+	if g.adaptive {
+		first = int(g.recent.Load())
+		if t, err := parse(g.layouts[first], value); err == nil {
+			return t, g.layouts[first], true
+		}
+	}
+	for i, layout := range g.layouts {
+		if g.adaptive && i == first {
+			continue
+		}
+		if t, err := parse(layout, value); err == nil {
+			if g.adaptive {
+				g.recent.Store(int32(i))
+			}
+			return t, layout, true
+		}
+	}
+	return time.Time{}, "", false
+}
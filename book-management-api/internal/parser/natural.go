@@ -0,0 +1,242 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	naturalAgo      = regexp.MustCompile(`^(\w+) (second|minute|hour|day|week|fortnight|month|year|decade)s? ago$`)
+	naturalIn       = regexp.MustCompile(`^in (\w+) (second|minute|hour|day|week|fortnight|month|year|decade)s?$`)
+	naturalFromNow  = regexp.MustCompile(`^(\w+) (second|minute|hour|day|week|fortnight|month|year|decade)s? (?:from now|later|hence)$`)
+	naturalWeekday  = regexp.MustCompile(`^(last|next|this) (monday|tuesday|wednesday|thursday|friday|saturday|sunday)$`)
+	naturalPeriod   = regexp.MustCompile(`^(last|next|this) (week|month|quarter|year|decade)$`)
+	naturalQuarter  = regexp.MustCompile(`^q([1-4])(?: (\d{4}))?$`)
+	naturalYearQ    = regexp.MustCompile(`^(\d{4}) q([1-4])$`)
+	naturalDecade   = regexp.MustCompile(`^(?:(early|mid|late)[- ]?)?(?:the )?(\d{3}0)s$`)
+	naturalYearPart = regexp.MustCompile(`^(early|mid|late)[- ]?(\d{4})$`)
+	naturalYear     = regexp.MustCompile(`^(\d{4})$`)
+	naturalMonth    = regexp.MustCompile(`^([a-z]+) (\d{4})$`)
+)
+
+var numberWords = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"january": time.January, "february": time.February, "march": time.March, "april": time.April,
+	"may": time.May, "june": time.June, "july": time.July, "august": time.August,
+	"september": time.September, "october": time.October, "november": time.November, "december": time.December,
+}
+
+// naturalDate is a parsed natural-language expression
+type naturalDate struct {
+	interval  Interval
+	precision Precision
+	// relative is set when the result depends on the clock
+	relative bool
+}
+
+// parseNatural evaluates relative and natural-language expressions such as
+// "yesterday", "last Tuesday", "3 days ago", "next month", "Q3 2021" or
+// "mid-1990s" against now. Periods are returned as intervals in now's
+// location; offsets of less than a day are instants.
+func parseNatural(dateStr string, now time.Time) (naturalDate, bool) {
+	s := strings.Join(strings.Fields(strings.ToLower(dateStr)), " ")
+	today := startOfDay(now)
+
+	switch s {
+	case "now", "right now":
+		return naturalDate{Interval{now, now}, PrecisionSecond, true}, true
+	case "today":
+		return dayOf(today, true), true
+	case "yesterday":
+		return dayOf(today.AddDate(0, 0, -1), true), true
+	case "tomorrow":
+		return dayOf(today.AddDate(0, 0, 1), true), true
+	case "day before yesterday", "the day before yesterday":
+		return dayOf(today.AddDate(0, 0, -2), true), true
+	case "day after tomorrow", "the day after tomorrow":
+		return dayOf(today.AddDate(0, 0, 2), true), true
+	}
+
+	if m := naturalAgo.FindStringSubmatch(s); m != nil {
+		return offset(now, m[1], m[2], -1)
+	}
+	if m := naturalIn.FindStringSubmatch(s); m != nil {
+		return offset(now, m[1], m[2], 1)
+	}
+	if m := naturalFromNow.FindStringSubmatch(s); m != nil {
+		return offset(now, m[1], m[2], 1)
+	}
+
+	if m := naturalWeekday.FindStringSubmatch(s); m != nil {
+		target := weekdays[m[2]]
+		var day time.Time
+		switch m[1] {
+		case "last":
+			back := (int(today.Weekday()) - int(target) + 7) % 7
+			if back == 0 {
+				back = 7
+			}
+			day = today.AddDate(0, 0, -back)
+		case "next":
+			ahead := (int(target) - int(today.Weekday()) + 7) % 7
+			if ahead == 0 {
+				ahead = 7
+			}
+			day = today.AddDate(0, 0, ahead)
+		default:
+			day = startOfWeek(today).AddDate(0, 0, (int(target)+6)%7)
+		}
+		return dayOf(day, true), true
+	}
+
+	if m := naturalPeriod.FindStringSubmatch(s); m != nil {
+		step := map[string]int{"last": -1, "this": 0, "next": 1}[m[1]]
+		var start, end time.Time
+		precision := PrecisionDay
+		switch m[2] {
+		case "week":
+			start = startOfWeek(today).AddDate(0, 0, 7*step)
+			end = start.AddDate(0, 0, 7)
+		case "month":
+			start = time.Date(now.Year(), now.Month()+time.Month(step), 1, 0, 0, 0, 0, now.Location())
+			end = start.AddDate(0, 1, 0)
+			precision = PrecisionMonth
+		case "quarter":
+			first := time.Month((int(now.Month())-1)/3*3 + 1)
+			start = time.Date(now.Year(), first+time.Month(3*step), 1, 0, 0, 0, 0, now.Location())
+			end = start.AddDate(0, 3, 0)
+			precision = PrecisionMonth
+		case "year":
+			start = time.Date(now.Year()+step, 1, 1, 0, 0, 0, 0, now.Location())
+			end = start.AddDate(1, 0, 0)
+			precision = PrecisionYear
+		case "decade":
+			start = time.Date(now.Year()/10*10+10*step, 1, 1, 0, 0, 0, 0, now.Location())
+			end = start.AddDate(10, 0, 0)
+			precision = PrecisionYear
+		}
+		return naturalDate{Interval{start, end}, precision, true}, true
+	}
+
+	if m := naturalQuarter.FindStringSubmatch(s); m != nil {
+		year, relative := now.Year(), true
+		if m[2] != "" {
+			year, _ = strconv.Atoi(m[2])
+			relative = false
+		}
+		quarter, _ := strconv.Atoi(m[1])
+		return quarterOf(year, quarter, now.Location(), relative), true
+	}
+	if m := naturalYearQ.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		quarter, _ := strconv.Atoi(m[2])
+		return quarterOf(year, quarter, now.Location(), false), true
+	}
+
+	if m := naturalDecade.FindStringSubmatch(s); m != nil {
+		decade, _ := strconv.Atoi(m[2])
+		// early, mid and late cover years 0-3, 3-6 and 7-9 of the decade
+		from, to := 0, 10
+		switch m[1] {
+		case "early":
+			from, to = 0, 4
+		case "mid":
+			from, to = 3, 7
+		case "late":
+			from, to = 7, 10
+		}
+		start := time.Date(decade+from, 1, 1, 0, 0, 0, 0, now.Location())
+		end := time.Date(decade+to, 1, 1, 0, 0, 0, 0, now.Location())
+		return naturalDate{Interval{start, end}, PrecisionYear, false}, true
+	}
+
+	if m := naturalYearPart.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[2])
+		// early, mid and late are the thirds of the year
+		first := map[string]time.Month{"early": time.January, "mid": time.May, "late": time.September}[m[1]]
+		start := time.Date(year, first, 1, 0, 0, 0, 0, now.Location())
+		return naturalDate{Interval{start, start.AddDate(0, 4, 0)}, PrecisionMonth, false}, true
+	}
+
+	if m := naturalYear.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		start := time.Date(year, 1, 1, 0, 0, 0, 0, now.Location())
+		return naturalDate{Interval{start, start.AddDate(1, 0, 0)}, PrecisionYear, false}, true
+	}
+
+	if m := naturalMonth.FindStringSubmatch(s); m != nil {
+		if month, ok := months[m[1]]; ok {
+			year, _ := strconv.Atoi(m[2])
+			start := time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+			return naturalDate{Interval{start, start.AddDate(0, 1, 0)}, PrecisionMonth, false}, true
+		}
+	}
+
+	return naturalDate{}, false
+}
+
+// offset moves now by a count of units in the given direction
+func offset(now time.Time, count, unit string, direction int) (naturalDate, bool) {
+	n, ok := numberWords[count]
+	if !ok {
+		var err error
+		if n, err = strconv.Atoi(count); err != nil {
+			return naturalDate{}, false
+		}
+	}
+	n *= direction
+
+	switch unit {
+	case "second":
+		t := now.Add(time.Duration(n) * time.Second)
+		return naturalDate{Interval{t, t}, PrecisionSecond, true}, true
+	case "minute":
+		t := now.Add(time.Duration(n) * time.Minute)
+		return naturalDate{Interval{t, t}, PrecisionMinute, true}, true
+	case "hour":
+		t := now.Add(time.Duration(n) * time.Hour)
+		return naturalDate{Interval{t, t}, PrecisionHour, true}, true
+	case "day":
+		return dayOf(startOfDay(now).AddDate(0, 0, n), true), true
+	case "week":
+		return dayOf(startOfDay(now).AddDate(0, 0, 7*n), true), true
+	case "fortnight":
+		return dayOf(startOfDay(now).AddDate(0, 0, 14*n), true), true
+	case "month":
+		return dayOf(startOfDay(now).AddDate(0, n, 0), true), true
+	case "year":
+		return dayOf(startOfDay(now).AddDate(n, 0, 0), true), true
+	default:
+		return dayOf(startOfDay(now).AddDate(10*n, 0, 0), true), true
+	}
+}
+
+func dayOf(day time.Time, relative bool) naturalDate {
+	return naturalDate{Interval{day, day.AddDate(0, 0, 1)}, PrecisionDay, relative}
+}
+
+func quarterOf(year, quarter int, loc *time.Location, relative bool) naturalDate {
+	start := time.Date(year, time.Month(3*(quarter-1)+1), 1, 0, 0, 0, 0, loc)
+	return naturalDate{Interval{start, start.AddDate(0, 3, 0)}, PrecisionMonth, relative}
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek returns the Monday starting t's ISO week
+func startOfWeek(t time.Time) time.Time {
+	return startOfDay(t).AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+}
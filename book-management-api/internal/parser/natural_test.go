@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNaturalDates(t *testing.T) {
+	// Wednesday, December 15, 2021
+	clock := func() time.Time { return time.Date(2021, 12, 15, 10, 30, 0, 0, time.UTC) }
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		input      string
+		start, end time.Time
+	}{
+		{"today", date(2021, 12, 15), date(2021, 12, 16)},
+		{"yesterday", date(2021, 12, 14), date(2021, 12, 15)},
+		{"the day after tomorrow", date(2021, 12, 17), date(2021, 12, 18)},
+		// Today is a Wednesday, so last and next are a week away
+		{"last Wednesday", date(2021, 12, 8), date(2021, 12, 9)},
+		{"next Wednesday", date(2021, 12, 22), date(2021, 12, 23)},
+		{"this Wednesday", date(2021, 12, 15), date(2021, 12, 16)},
+		{"last Friday", date(2021, 12, 10), date(2021, 12, 11)},
+		{"3 days ago", date(2021, 12, 12), date(2021, 12, 13)},
+		{"in 2 weeks", date(2021, 12, 29), date(2021, 12, 30)},
+		{"a month from now", date(2022, 1, 15), date(2022, 1, 16)},
+		{"next month", date(2022, 1, 1), date(2022, 2, 1)},
+		{"last month", date(2021, 11, 1), date(2021, 12, 1)},
+		{"this quarter", date(2021, 10, 1), date(2022, 1, 1)},
+		{"last quarter", date(2021, 7, 1), date(2021, 10, 1)},
+		{"next year", date(2022, 1, 1), date(2023, 1, 1)},
+		{"Q3 2021", date(2021, 7, 1), date(2021, 10, 1)},
+		{"2021 Q1", date(2021, 1, 1), date(2021, 4, 1)},
+		{"q2", date(2021, 4, 1), date(2021, 7, 1)},
+		{"1990s", date(1990, 1, 1), date(2000, 1, 1)},
+		{"the 1990s", date(1990, 1, 1), date(2000, 1, 1)},
+		{"early-1990s", date(1990, 1, 1), date(1994, 1, 1)},
+		{"mid-1990s", date(1993, 1, 1), date(1997, 1, 1)},
+		{"late 1990s", date(1997, 1, 1), date(2000, 1, 1)},
+		{"mid 2021", date(2021, 5, 1), date(2021, 9, 1)},
+		{"March 2021", date(2021, 3, 1), date(2021, 4, 1)},
+		{"2021", date(2021, 1, 1), date(2022, 1, 1)},
+	}
+
+	p := NewDateTimeParser(WithClock(clock))
+	for _, tt := range tests {
+		got, err := p.ParseRange(tt.input)
+		if err != nil {
+			t.Errorf("parse %q: %v", tt.input, err)
+			continue
+		}
+		if !got.Start.Equal(tt.start) || !got.End.Equal(tt.end) {
+			t.Errorf("parse %q = %s, want %s", tt.input, got, Interval{tt.start, tt.end})
+		}
+	}
+
+	got, err := p.ParseDetailed("2 hours ago")
+	if want := clock().Add(-2 * time.Hour); err != nil || !got.Time.Equal(want) || got.Precision != PrecisionHour {
+		t.Errorf("parse %q = %s %s, %v, want %s", "2 hours ago", got.Time, got.Precision, err, want)
+	}
+
+	for _, input := range []string{"last blursday", "in many days", "q5 2021", "Smarch 2021"} {
+		if _, err := p.Parse(input); err == nil {
+			t.Errorf("parse %q accepted an invalid expression", input)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package parser
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultLayouts are tried in order for every input
+var defaultLayouts = []string{
+	// RFC3339 and ISO 8601 variants
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02T15:04:05.000Z07:00",
+	"2006-01-02T15:04:05Z",
+	"2006-01-02T15:04:05.000Z",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05.000",
+
+	// Date only formats
+	"2006-01-02",
+	"2006/01/02",
+	"2006/1/2",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"Jan 02, 2006",
+	"January 02, 2006",
+	"Jan 2 2006",
+	"January 2 2006",
+	"2 Jan 2006",
+	"02 Jan 2006",
+	"2 January 2006",
+	"02 January 2006",
+
+	// Time with date formats
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 3:04:05 PM",
+	"Jan 2, 2006 15:04:05",
+	"Jan 2, 2006 3:04:05 PM",
+	"January 2, 2006 15:04:05",
+	"January 2, 2006 3:04:05 PM",
+	"Jan 2, 2006 15:04",
+	"January 2, 2006 15:04",
+	"January 2 2006 15:04:05",
+	"January 2 2006 15:04",
+	"2 Jan 2006 15:04:05",
+	"2 January 2006 15:04:05",
+	"2 Jan 2006 15:04",
+	"2 January 2006 15:04",
+
+	// Time only formats (will use current date)
+	"15:04:05",
+	"15:04:05.000",
+	"3:04:05 PM",
+	"3:04 PM",
+	"15:04",
+
+	// Unix timestamp handling is done separately
+}
+
+// monthFirstLayouts and dayFirstLayouts are the numeric layouts tried
+// according to the date order
+var (
+	monthFirstLayouts = []string{
+		"01/02/2006",
+		"1/2/2006",
+		"01-02-2006",
+		"1-2-2006",
+		"01/02/2006 15:04:05",
+		"1/2/2006 15:04:05",
+		"01/02/2006 3:04:05 PM",
+		"1/2/2006 3:04:05 PM",
+	}
+	dayFirstLayouts = []string{
+		"02/01/2006", // DD/MM/YYYY
+		"2/1/2006",   // D/M/YYYY
+		"02-01-2006", // DD-MM-YYYY
+		"2-1-2006",   // D-M-YYYY
+		"02/01/2006 15:04:05",
+		"2/1/2006 15:04:05",
+		"02/01/2006 3:04:05 PM",
+		"2/1/2006 3:04:05 PM",
+	}
+)
+
+// DefaultLayouts returns a copy of the layouts a parser tries by default,
+// apart from the numeric ones that depend on the date order
+func DefaultLayouts() []string {
+	return append([]string(nil), defaultLayouts...)
+}
+
+// parserConfig collects the options of NewDateTimeParser
+type parserConfig struct {
+	layouts   []string
+	options   ParseOptions
+	location  *time.Location
+	calendars []Calendar
+	format    DateFormat
+	now       func() time.Time
+}
+
+// Option configures a DateTimeParser
+type Option func(*parserConfig)
+
+// WithLayouts replaces the default layouts, keeping the numeric ones that
+// depend on the date order
+func WithLayouts(layouts ...string) Option {
+	return func(c *parserConfig) {
+		c.layouts = append([]string(nil), layouts...)
+	}
+}
+
+// WithExtraLayouts adds layouts tried after the others
+func WithExtraLayouts(layouts ...string) Option {
+	return func(c *parserConfig) {
+		c.layouts = append(c.layouts, layouts...)
+	}
+}
+
+// WithPriority tries the given layouts first, in the given order, adding
+// those the parser does not have yet
+func WithPriority(layouts ...string) Option {
+	return func(c *parserConfig) {
+		first := make(map[string]bool, len(layouts))
+		for _, layout := range layouts {
+			first[layout] = true
+		}
+		ordered := append([]string(nil), layouts...)
+		for _, layout := range c.layouts {
+			if !first[layout] {
+				ordered = append(ordered, layout)
+			}
+		}
+		c.layouts = ordered
+	}
+}
+
+// WithLocation sets the zone of input without a UTC offset (UTC by default)
+// and of relative expressions such as "today"
+func WithLocation(loc *time.Location) Option {
+	return func(c *parserConfig) {
+		c.location = loc
+	}
+}
+
+// WithCalendars sets the non-Gregorian calendars the parser converts,
+// replacing DefaultCalendars; call it without arguments to read Gregorian
+// dates only
+func WithCalendars(calendars ...Calendar) Option {
+	return func(c *parserConfig) {
+		c.calendars = append([]Calendar(nil), calendars...)
+	}
+}
+
+// WithDateFormat sets the format responses write times in unless a request
+// asks for another, FormatRFC3339 by default
+func WithDateFormat(format DateFormat) Option {
+	return func(c *parserConfig) {
+		c.format = format
+	}
+}
+
+// WithLocale sets the language of month and weekday names, such as "fr"
+func WithLocale(tag string) Option {
+	return func(c *parserConfig) {
+		c.options.Locale = tag
+	}
+}
+
+// WithDateOrder sets how numeric dates such as 03/04/2020 are read
+func WithDateOrder(order DateOrder) Option {
+	return func(c *parserConfig) {
+		c.options.Order = order
+	}
+}
+
+// WithStrict rejects numeric dates that are valid both month-first and
+// day-first instead of reading them month-first
+func WithStrict(strict bool) Option {
+	return func(c *parserConfig) {
+		c.options.Strict = strict
+	}
+}
+
+// WithUnixUnit sets the unit of Unix timestamps without a unit suffix
+func WithUnixUnit(unit UnixUnit) Option {
+	return func(c *parserConfig) {
+		c.options.UnixUnit = unit
+	}
+}
+
+// WithClock sets the clock relative expressions are evaluated against, for
+// example to pin "today" in tests
+func WithClock(now func() time.Time) Option {
+	return func(c *parserConfig) {
+		c.now = now
+	}
+}
+
+// NewDateTimeParser creates a parser with the default layouts, changed by opts
+func NewDateTimeParser(opts ...Option) *DateTimeParser {
+	config := parserConfig{
+		layouts:   DefaultLayouts(),
+		location:  time.UTC,
+		calendars: DefaultCalendars(),
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	p := &DateTimeParser{
+		monthFirst: newLayoutSet(monthFirstLayouts),
+		dayFirst:   newLayoutSet(dayFirstLayouts),
+		options:    config.options,
+		location:   config.location,
+		calendars:  config.calendars,
+		format:     config.format,
+		now:        config.now,
+	}
+	p.formats.Store(newLayoutSet(config.layouts))
+	return p
+}
+
+var (
+	defaultParser     *DateTimeParser
+	defaultParserOnce sync.Once
+)
+
+// Default returns the parser used by the package-level functions
+func Default() *DateTimeParser {
+	defaultParserOnce.Do(func() {
+		defaultParser = NewDateTimeParser()
+	})
+	return defaultParser
+}
+
+// GetParser returns the parser used by the package-level functions.
+//
+// Deprecated: use Default, or NewDateTimeParser for a parser of your own.
+func GetParser() *DateTimeParser {
+	return Default()
+}
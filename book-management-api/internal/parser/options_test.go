@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsersAreIndependent(t *testing.T) {
+	custom := NewDateTimeParser(WithExtraLayouts("02.01.2006"))
+	plain := NewDateTimeParser()
+
+	if _, err := custom.Parse("31.12.2020"); err != nil {
+		t.Errorf("custom parser: %v", err)
+	}
+	if _, err := plain.Parse("31.12.2020"); err == nil {
+		t.Error("plain parser accepted a layout added to another parser")
+	}
+
+	plain.AddFormat("2006.01.02")
+	if _, err := custom.Parse("2020.12.31"); err == nil {
+		t.Error("AddFormat on one parser changed another")
+	}
+}
+
+func TestParserOptions(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*3600)
+	clock := func() time.Time { return time.Date(2021, 3, 4, 23, 30, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name   string
+		parser *DateTimeParser
+		input  string
+		want   time.Time
+	}{
+		{"location", NewDateTimeParser(WithLocation(jakarta)), "2021-03-04 10:00:00", time.Date(2021, 3, 4, 10, 0, 0, 0, jakarta)},
+		{"explicit offset wins", NewDateTimeParser(WithLocation(jakarta)), "2021-03-04T10:00:00Z", time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC)},
+		{"clock", NewDateTimeParser(WithClock(clock)), "today", time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{"clock in location", NewDateTimeParser(WithClock(clock), WithLocation(jakarta)), "today", time.Date(2021, 3, 5, 0, 0, 0, 0, jakarta)},
+		{"date order", NewDateTimeParser(WithDateOrder(OrderDayFirst)), "03/04/2021", time.Date(2021, 4, 3, 0, 0, 0, 0, time.UTC)},
+		{"locale", NewDateTimeParser(WithLocale("de")), "3. März 2021", time.Date(2021, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{"unix unit", NewDateTimeParser(WithUnixUnit(UnixMillis)), "86400000", time.Date(1970, 1, 2, 0, 0, 0, 0, time.UTC)},
+		// "01.02.2006" would otherwise lose to the earlier day-first layout
+		{"priority", NewDateTimeParser(WithExtraLayouts("02.01.2006"), WithPriority("01.02.2006")), "03.04.2021", time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := tt.parser.Parse(tt.input)
+		if err != nil {
+			t.Errorf("%s: parse %q: %v", tt.name, tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) || got.Location().String() != tt.want.Location().String() {
+			t.Errorf("%s: parse %q = %s, want %s", tt.name, tt.input, got, tt.want)
+		}
+	}
+
+	if _, err := NewDateTimeParser(WithStrict(true)).Parse("03/04/2021"); err == nil {
+		t.Error("strict parser accepted an ambiguous date")
+	}
+	if _, err := NewDateTimeParser(WithLayouts("2006-01-02")).Parse("January 2, 2021"); err == nil {
+		t.Error("WithLayouts kept the default layouts")
+	}
+}
+
+func TestAmbiguityReported(t *testing.T) {
+	p := NewDateTimeParser()
+
+	got, err := p.ParseDetailed("03/04/2021")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Ambiguity == nil {
+		t.Fatal("ambiguous date parsed without Ambiguity")
+	}
+	if !got.Ambiguity.DayFirst.Equal(time.Date(2021, 4, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("day-first reading = %s, want April 3", got.Ambiguity.DayFirst)
+	}
+
+	for _, input := range []string{"13/04/2021", "04/04/2021", "2021-03-04"} {
+		if got, err := p.ParseDetailed(input); err != nil || got.Ambiguity != nil {
+			t.Errorf("%q: Ambiguity = %v, err = %v, want neither", input, got.Ambiguity, err)
+		}
+	}
+}
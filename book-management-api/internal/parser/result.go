@@ -0,0 +1,173 @@
+package parser
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Precision is the smallest unit given in a parsed date
+type Precision int
+
+const (
+	PrecisionYear Precision = iota
+	PrecisionMonth
+	PrecisionDay
+	PrecisionHour
+	PrecisionMinute
+	PrecisionSecond
+	PrecisionSubSecond
+)
+
+var precisionNames = []string{"year", "month", "day", "hour", "minute", "second", "sub-second"}
+
+func (p Precision) String() string {
+	if p < 0 || int(p) >= len(precisionNames) {
+		return "unknown"
+	}
+	return precisionNames[p]
+}
+
+// MarshalText encodes the precision by name
+func (p Precision) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// Result describes how a date string was parsed
+type Result struct {
+	Time time.Time
+	// End is the exclusive end of the period the input denotes, e.g. the
+	// next day for "2020-01-02", or Time for an instant
+	End time.Time
+	// Layout is the Go layout that matched, unix, unix_ms, unix_us or
+	// unix_ns for Unix timestamps, natural for natural-language expressions,
+	// or the calendar name for non-Gregorian dates
+	Layout    string
+	Precision Precision
+	// ExplicitOffset is set when the input carried a UTC offset or zone;
+	// otherwise the zone was assumed
+	ExplicitOffset bool
+	// TimeOnly is set for inputs without a date, such as "15:04", which are
+	// dated January 1 of year 0
+	TimeOnly bool
+	// Calendar is the date as written in a non-Gregorian calendar, such as
+	// "Reiwa 3"; nil for Gregorian input
+	Calendar *CalendarDate
+	// Ambiguity holds both readings of a numeric date that was valid
+	// month-first and day-first and was read month-first; nil otherwise
+	Ambiguity *AmbiguousDateError
+	Warnings  []string
+}
+
+// Result warnings
+const (
+	WarningTimeOnly      = "no date given, January 1 of year 0 assumed"
+	WarningAssumedZone   = "no UTC offset given, %s assumed"
+	WarningAmbiguousDate = "ambiguous date %q read month-first as %s rather than day-first as %s"
+	WarningRelative      = "relative date evaluated against %s"
+)
+
+// Interval returns the period the input denotes
+func (r Result) Interval() Interval {
+	return Interval{Start: r.Time, End: r.End}
+}
+
+// periodEnd returns the end of the period of the given precision starting at t
+func periodEnd(t time.Time, precision Precision) time.Time {
+	switch precision {
+	case PrecisionYear:
+		return t.AddDate(1, 0, 0)
+	case PrecisionMonth:
+		return t.AddDate(0, 1, 0)
+	case PrecisionDay:
+		return t.AddDate(0, 0, 1)
+	case PrecisionHour:
+		return t.Add(time.Hour)
+	case PrecisionMinute:
+		return t.Add(time.Minute)
+	case PrecisionSecond:
+		return t.Add(time.Second)
+	default:
+		return t
+	}
+}
+
+// probe is a reference time whose fields are all distinct, used to find out
+// which fields a layout contains
+var probe = time.Date(2001, 2, 3, 4, 5, 6, 789_000_000, time.UTC)
+
+// layoutHas reports whether formatting with layout shows the difference
+// between probe and changed
+func layoutHas(layout string, changed time.Time) bool {
+	return probe.Format(layout) != changed.Format(layout)
+}
+
+// layoutTraits are the result fields a layout implies, whatever the input
+type layoutTraits struct {
+	precision      Precision
+	timeOnly       bool
+	explicitOffset bool
+}
+
+// traitsCache maps layouts to their layoutTraits, since finding them takes
+// several probe formats
+var traitsCache sync.Map
+
+func traitsOf(layout string) layoutTraits {
+	if traits, ok := traitsCache.Load(layout); ok {
+		return traits.(layoutTraits)
+	}
+
+	var traits layoutTraits
+	switch {
+	case layoutHas(layout, probe.Add(time.Millisecond)):
+		traits.precision = PrecisionSubSecond
+	case layoutHas(layout, probe.Add(time.Second)):
+		traits.precision = PrecisionSecond
+	case layoutHas(layout, probe.Add(time.Minute)):
+		traits.precision = PrecisionMinute
+	case layoutHas(layout, probe.Add(time.Hour)):
+		traits.precision = PrecisionHour
+	case layoutHas(layout, probe.AddDate(0, 0, 1)):
+		traits.precision = PrecisionDay
+	case layoutHas(layout, probe.AddDate(0, 1, 0)):
+		traits.precision = PrecisionMonth
+	}
+	traits.timeOnly = !layoutHas(layout, probe.AddDate(1, 0, 0))
+	traits.explicitOffset = strings.Contains(layout, "Z07") || strings.Contains(layout, "-07") || strings.Contains(layout, "MST") || literalZ(layout)
+
+	traitsCache.Store(layout, traits)
+	return traits
+}
+
+// literalZ reports whether layout ends in a literal Z, as in
+// "2006-01-02T15:04:05Z", which marks UTC just as the Z of Z07:00 does
+func literalZ(layout string) bool {
+	return strings.HasSuffix(layout, "Z")
+}
+
+// describeLayout derives the result fields implied by a layout
+func describeLayout(layout string, t time.Time) Result {
+	return describeTraits(layout, traitsOf(layout), t)
+}
+
+// describeTraits is describeLayout with the layout's traits already known
+func describeTraits(layout string, traits layoutTraits, t time.Time) Result {
+	r := Result{
+		Time:           t,
+		Layout:         layout,
+		Precision:      traits.precision,
+		TimeOnly:       traits.timeOnly,
+		ExplicitOffset: traits.explicitOffset,
+	}
+	if t.Nanosecond() != 0 {
+		// Layouts without a fraction still accept one after the seconds
+		r.Precision = PrecisionSubSecond
+	}
+
+	r.End = periodEnd(t, r.Precision)
+	if r.TimeOnly {
+		r.Warnings = append(r.Warnings, WarningTimeOnly)
+	}
+	return r
+}
@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLayoutTraits(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+	assumed := fmt.Sprintf(WarningAssumedZone, ny)
+
+	tests := []struct {
+		family         string
+		layout         string
+		input          string
+		want           time.Time
+		precision      Precision
+		explicitOffset bool
+		timeOnly       bool
+		warnings       []string
+	}{
+		{
+			family: "RFC 3339", layout: time.RFC3339, input: "2021-03-02T15:04:05+01:00",
+			want:      time.Date(2021, 3, 2, 14, 4, 5, 0, time.UTC),
+			precision: PrecisionSecond, explicitOffset: true,
+		},
+		{
+			family: "RFC 3339 fraction", layout: time.RFC3339Nano, input: "2021-03-02T15:04:05.25Z",
+			want:      time.Date(2021, 3, 2, 15, 4, 5, 250_000_000, time.UTC),
+			precision: PrecisionSubSecond, explicitOffset: true,
+		},
+		{
+			family: "literal Z", layout: "2006-01-02T15:04:05Z", input: "2021-03-02T15:04:05Z",
+			want:      time.Date(2021, 3, 2, 15, 4, 5, 0, time.UTC),
+			precision: PrecisionSecond, explicitOffset: true,
+		},
+		{
+			family: "literal Z fraction", layout: "2006-01-02T15:04:05.000Z", input: "2021-03-02T15:04:05.250Z",
+			want:      time.Date(2021, 3, 2, 15, 4, 5, 250_000_000, time.UTC),
+			precision: PrecisionSubSecond, explicitOffset: true,
+		},
+		{
+			family: "zone abbreviation", layout: time.RFC1123, input: "Tue, 02 Mar 2021 15:04:05 UTC",
+			want:      time.Date(2021, 3, 2, 15, 4, 5, 0, time.UTC),
+			precision: PrecisionSecond, explicitOffset: true,
+		},
+		{
+			family: "ISO local", layout: "2006-01-02T15:04:05", input: "2021-03-02T15:04:05",
+			want:      time.Date(2021, 3, 2, 15, 4, 5, 0, ny),
+			precision: PrecisionSecond, warnings: []string{assumed},
+		},
+		{
+			family: "date only", layout: "2006-01-02", input: "2021-03-02",
+			want:      time.Date(2021, 3, 2, 0, 0, 0, 0, ny),
+			precision: PrecisionDay,
+		},
+		{
+			family: "named month", layout: "January 2, 2006", input: "March 2, 2021",
+			want:      time.Date(2021, 3, 2, 0, 0, 0, 0, ny),
+			precision: PrecisionDay,
+		},
+		{
+			family: "month and year", layout: "January 2006", input: "March 2021",
+			want:      time.Date(2021, 3, 1, 0, 0, 0, 0, ny),
+			precision: PrecisionMonth,
+		},
+		{
+			family: "12-hour clock", layout: "2006-01-02 3:04 PM", input: "2021-03-02 3:04 PM",
+			want:      time.Date(2021, 3, 2, 15, 4, 0, 0, ny),
+			precision: PrecisionMinute, warnings: []string{assumed},
+		},
+		{
+			family: "time only", layout: "15:04", input: "15:04",
+			want:      time.Date(0, 1, 1, 15, 4, 0, 0, ny),
+			precision: PrecisionMinute, timeOnly: true, warnings: []string{WarningTimeOnly, assumed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.family, func(t *testing.T) {
+			p := NewDateTimeParser(WithLayouts(tt.layout), WithLocation(ny))
+			got, err := p.ParseDetailed(tt.input)
+			if err != nil {
+				t.Fatalf("parse %q: %v", tt.input, err)
+			}
+			if !got.Time.Equal(tt.want) || got.Layout != tt.layout {
+				t.Errorf("parse %q = %s with %q, want %s with %q", tt.input, got.Time, got.Layout, tt.want, tt.layout)
+			}
+			if got.Precision != tt.precision || got.ExplicitOffset != tt.explicitOffset || got.TimeOnly != tt.timeOnly {
+				t.Errorf("parse %q: precision %s, explicit offset %v, time only %v; want %s, %v, %v",
+					tt.input, got.Precision, got.ExplicitOffset, got.TimeOnly, tt.precision, tt.explicitOffset, tt.timeOnly)
+			}
+			if !reflect.DeepEqual(got.Warnings, tt.warnings) {
+				t.Errorf("parse %q: warnings %q, want %q", tt.input, got.Warnings, tt.warnings)
+			}
+		})
+	}
+}
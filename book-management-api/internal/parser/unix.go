@@ -0,0 +1,203 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UnixUnit is the unit of a Unix timestamp
+type UnixUnit int
+
+const (
+	// UnixAuto picks the unit from the magnitude of the value: the first of
+	// seconds, milliseconds, microseconds and nanoseconds giving a plausible
+	// time. Unsigned values need at least 9 integer digits to be read this
+	// way, so years such as 2021 are not taken for timestamps; shorter signed
+	// values, such as -86400, are read as seconds.
+	UnixAuto UnixUnit = iota
+	UnixSeconds
+	UnixMillis
+	UnixMicros
+	UnixNanos
+)
+
+// Plausible Unix timestamps fall within [MinUnixTime, MaxUnixTime)
+var (
+	MinUnixTime = time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+	MaxUnixTime = time.Date(2200, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// minAutoDigits is the number of integer digits from which a bare number is
+// taken for a Unix timestamp; 100000000 is March 3, 1973
+const minAutoDigits = 9
+
+var unixUnits = []struct {
+	unit    UnixUnit
+	suffix  string
+	layout  string
+	perUnit int64 // nanoseconds per unit
+}{
+	{UnixSeconds, "s", "unix", int64(time.Second)},
+	{UnixMillis, "ms", "unix_ms", int64(time.Millisecond)},
+	{UnixMicros, "us", "unix_us", int64(time.Microsecond)},
+	{UnixNanos, "ns", "unix_ns", int64(time.Nanosecond)},
+}
+
+// ParseUnixUnit reads "s", "ms", "us" and "ns" (or "auto"); an empty value is UnixAuto
+func ParseUnixUnit(value string) (UnixUnit, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || value == "auto" {
+		return UnixAuto, nil
+	}
+	if value == "µs" {
+		value = "us"
+	}
+	for _, u := range unixUnits {
+		if u.suffix == value {
+			return u.unit, nil
+		}
+	}
+	return UnixAuto, fmt.Errorf("invalid unix unit %q, expected s, ms, us, ns or auto", value)
+}
+
+// String returns the suffix accepted by ParseUnixUnit
+func (u UnixUnit) String() string {
+	for _, unit := range unixUnits {
+		if unit.unit == u {
+			return unit.suffix
+		}
+	}
+	return "auto"
+}
+
+// errNotTimestamp means the input does not look like a Unix timestamp and
+// other layouts should be tried
+var errNotTimestamp = errors.New("not a numeric timestamp")
+
+// parseUnixTimestamp reads an optionally signed Unix timestamp with an
+// optional fraction and unit suffix, such as 1716700000, -86400s,
+// 1716700000.123 or 1716700000123ms. A suffix overrides unit. The result is
+// in UTC.
+func parseUnixTimestamp(dateStr string, unit UnixUnit) (Result, error) {
+	s := dateStr
+	signed, negative := false, false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		signed, negative = true, s[0] == '-'
+		s = s[1:]
+	}
+
+	// Dates such as 2021-03-04 are turned away at the first separator
+	if i := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' }); i == 0 || i > 0 && s[i] != '.' && !isLetter(s[i]) && s[i] != ' ' {
+		return Result{}, errNotTimestamp
+	}
+
+	digits := strings.TrimRightFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+	suffix := strings.ToLower(s[len(digits):])
+	if suffix == "µs" {
+		suffix = "us"
+	}
+	intPart, fracPart, hasFrac := strings.Cut(digits, ".")
+	if intPart == "" || !allDigits(intPart) || !allDigits(fracPart) || hasFrac && fracPart == "" {
+		return Result{}, errNotTimestamp
+	}
+
+	// "1990s" is a decade, read by the natural-language grammar
+	if unit == UnixAuto && suffix == "s" && !signed && !hasFrac && len(intPart) == 4 && intPart[3] == '0' {
+		return Result{}, errNotTimestamp
+	}
+
+	if suffix != "" {
+		found := false
+		for _, u := range unixUnits {
+			if u.suffix == suffix {
+				unit, found = u.unit, true
+			}
+		}
+		if !found {
+			return Result{}, errNotTimestamp
+		}
+	}
+
+	if unit == UnixAuto && len(strings.TrimLeft(intPart, "0")) < minAutoDigits {
+		// No date layout starts with a sign, so short signed values are seconds
+		if !signed {
+			return Result{}, errNotTimestamp
+		}
+		unit = UnixSeconds
+	}
+	if unit == UnixAuto {
+		for _, u := range unixUnits {
+			if r, err := unixTime(negative, intPart, fracPart, u.unit); err == nil {
+				return r, nil
+			}
+		}
+		return Result{}, fmt.Errorf("timestamp %s is out of range in any unit (%s to %s)",
+			dateStr, MinUnixTime.Format(time.RFC3339), MaxUnixTime.Format(time.RFC3339))
+	}
+
+	r, err := unixTime(negative, intPart, fracPart, unit)
+	if err != nil {
+		return Result{}, fmt.Errorf("timestamp %s: %w", dateStr, err)
+	}
+	return r, nil
+}
+
+// unixTime converts the parts of a timestamp in the given unit, checking the
+// result is plausible
+func unixTime(negative bool, intPart, fracPart string, unit UnixUnit) (Result, error) {
+	u := unixUnits[unit-UnixSeconds]
+	outOfRange := fmt.Errorf("out of range in %s (%s to %s)", u.suffix,
+		MinUnixTime.Format(time.RFC3339), MaxUnixTime.Format(time.RFC3339))
+
+	value, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil {
+		return Result{}, outOfRange
+	}
+	unitsPerSecond := int64(time.Second) / u.perUnit
+	sec, nsec := value/unitsPerSecond, value%unitsPerSecond*u.perUnit
+
+	// Keep the fraction digits that fit in the unit's nanoseconds
+	if fracPart != "" {
+		width := len(strconv.FormatInt(u.perUnit, 10)) - 1
+		frac := (fracPart + strings.Repeat("0", width))[:width]
+		if width > 0 {
+			n, _ := strconv.ParseInt(frac, 10, 64)
+			nsec += n
+		}
+	}
+	if negative {
+		sec, nsec = -sec, -nsec
+	}
+
+	t := time.Unix(sec, nsec).UTC()
+	if t.Before(MinUnixTime) || !t.Before(MaxUnixTime) {
+		return Result{}, outOfRange
+	}
+
+	r := Result{Time: t, Layout: u.layout, Precision: PrecisionSubSecond, ExplicitOffset: true}
+	if unit == UnixSeconds && fracPart == "" {
+		r.Precision = PrecisionSecond
+	}
+	r.End = periodEnd(t, r.Precision)
+	return r, nil
+}
+
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isShortNumber reports whether dateStr is an unsigned number without a unit
+// suffix that is too short to be read as a Unix timestamp in UnixAuto mode
+func isShortNumber(dateStr string) bool {
+	intPart, fracPart, _ := strings.Cut(dateStr, ".")
+	return intPart != "" && allDigits(intPart) && allDigits(fracPart) &&
+		len(strings.TrimLeft(intPart, "0")) < minAutoDigits
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseUnixTimestamp(t *testing.T) {
+	tests := []struct {
+		input     string
+		unit      UnixUnit
+		want      time.Time
+		layout    string
+		precision Precision
+	}{
+		{"1716700000", UnixAuto, time.Unix(1716700000, 0), "unix", PrecisionSecond},
+		{"946684799", UnixAuto, time.Date(1999, 12, 31, 23, 59, 59, 0, time.UTC), "unix", PrecisionSecond},
+		{"-100000000", UnixAuto, time.Unix(-100000000, 0), "unix", PrecisionSecond},
+		{"+1716700000", UnixAuto, time.Unix(1716700000, 0), "unix", PrecisionSecond},
+		{"1716700000.123", UnixAuto, time.Unix(1716700000, 123_000_000), "unix", PrecisionSubSecond},
+		{"-1.5s", UnixAuto, time.Unix(-2, 500_000_000), "unix", PrecisionSubSecond},
+		{"1716700000123", UnixAuto, time.UnixMilli(1716700000123), "unix_ms", PrecisionSubSecond},
+		{"946684799000", UnixAuto, time.UnixMilli(946684799000), "unix_ms", PrecisionSubSecond},
+		{"1716700000123456", UnixAuto, time.UnixMicro(1716700000123456), "unix_us", PrecisionSubSecond},
+		{"1716700000123456789", UnixAuto, time.Unix(0, 1716700000123456789), "unix_ns", PrecisionSubSecond},
+		{"1716700000123.5ms", UnixAuto, time.Unix(1716700000, 123_500_000), "unix_ms", PrecisionSubSecond},
+		{"86400s", UnixAuto, time.Date(1970, 1, 2, 0, 0, 0, 0, time.UTC), "unix", PrecisionSecond},
+		{"-86400", UnixAuto, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), "unix", PrecisionSecond},
+		{"+86400.5", UnixAuto, time.Date(1970, 1, 2, 0, 0, 0, 500_000_000, time.UTC), "unix", PrecisionSubSecond},
+		{"1716700000000µs", UnixAuto, time.UnixMicro(1716700000000), "unix_us", PrecisionSubSecond},
+		{"86400000", UnixMillis, time.Date(1970, 1, 2, 0, 0, 0, 0, time.UTC), "unix_ms", PrecisionSubSecond},
+		// A suffix wins over the unit option
+		{"86400s", UnixMillis, time.Date(1970, 1, 2, 0, 0, 0, 0, time.UTC), "unix", PrecisionSecond},
+	}
+	for _, tt := range tests {
+		got, err := parseUnixTimestamp(tt.input, tt.unit)
+		if err != nil {
+			t.Errorf("parseUnixTimestamp(%q, %s): %v", tt.input, tt.unit, err)
+			continue
+		}
+		if !got.Time.Equal(tt.want) || got.Time.Location() != time.UTC || got.Layout != tt.layout || got.Precision != tt.precision {
+			t.Errorf("parseUnixTimestamp(%q, %s) = %s %s %s, want %s %s %s", tt.input, tt.unit,
+				got.Time, got.Layout, got.Precision, tt.want.UTC(), tt.layout, tt.precision)
+		}
+	}
+}
+
+func TestParseUnixTimestampRejects(t *testing.T) {
+	notTimestamps := []string{"2021", "20210304", "12345678", "2021-03-04", "1st", "1716700000.", "1716700000 ms", "abc", "1990s", "2000s", "1980s"}
+	for _, input := range notTimestamps {
+		if _, err := parseUnixTimestamp(input, UnixAuto); err != errNotTimestamp {
+			t.Errorf("parseUnixTimestamp(%q) = %v, want errNotTimestamp", input, err)
+		}
+	}
+
+	if _, err := NewDateTimeParser().Parse("12345"); err == nil || !strings.Contains(err.Error(), "unit suffix") {
+		t.Errorf("Parse(\"12345\") = %v, want an error naming the unit suffix", err)
+	}
+
+	for _, input := range []string{"1990s", "2000s"} {
+		got, err := NewDateTimeParser().ParseDetailed(input)
+		if err != nil || got.Layout != "natural" || got.Precision != PrecisionYear {
+			t.Errorf("ParseDetailed(%q) = %s %s, %v, want a natural-language decade", input, got.Layout, got.Precision, err)
+		}
+	}
+
+	outOfRange := []struct {
+		input string
+		unit  UnixUnit
+	}{
+		{"99999999999999999999", UnixAuto},
+		{"-99999999999999999999", UnixAuto},
+		{"7258118400", UnixSeconds},
+		{"1716700000123", UnixSeconds},
+	}
+	for _, tt := range outOfRange {
+		if _, err := parseUnixTimestamp(tt.input, tt.unit); err == nil || err == errNotTimestamp {
+			t.Errorf("parseUnixTimestamp(%q, %s) = %v, want an out of range error", tt.input, tt.unit, err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package requestctx
+
+import (
+	"crypto/subtle"
+	"errors"
+	"os"
+	"strings"
+)
+
+var (
+	// ErrAdminDisabled is returned when no admin token is configured
+	ErrAdminDisabled = errors.New("admin endpoints are disabled, set ADMIN_TOKEN to enable them")
+	// ErrAdminUnauthorized is returned for a missing or wrong admin token
+	ErrAdminUnauthorized = errors.New("missing or invalid admin token")
+)
+
+// AdminToken is the bearer token the /admin endpoints require. An empty token
+// disables them.
+type AdminToken string
+
+// AdminTokenFromEnv reads ADMIN_TOKEN; by default the admin endpoints are off
+func AdminTokenFromEnv() AdminToken {
+	return AdminToken(strings.TrimSpace(os.Getenv("ADMIN_TOKEN")))
+}
+
+// Check verifies an Authorization header of the form "Bearer <token>"
+func (t AdminToken) Check(authorization string) error {
+	if t == "" {
+		return ErrAdminDisabled
+	}
+	scheme, token, found := strings.Cut(strings.TrimSpace(authorization), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ErrAdminUnauthorized
+	}
+	if subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(t)) != 1 {
+		return ErrAdminUnauthorized
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package requestctx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// IDHeader is the header used to accept and echo request IDs
+	IDHeader = "X-Request-ID"
+	// ActorHeader identifies who is making the request, for the audit trail
+	ActorHeader = "X-Actor"
+	// AnonymousActor is recorded when no actor header is sent
+	AnonymousActor = "anonymous"
+)
+
+// Info describes the request a context belongs to
+type Info struct {
+	ID       string
+	Method   string
+	Route    string
+	ClientIP string
+	Actor    string
+}
+
+type contextKey struct{}
+
+// WithInfo returns a copy of ctx carrying info
+func WithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, contextKey{}, &info)
+}
+
+// WithRoute returns a copy of ctx whose request info carries the matched
+// route pattern
+func WithRoute(ctx context.Context, route string) context.Context {
+	info, ok := FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	info.Route = route
+	return WithInfo(ctx, info)
+}
+
+// FromContext returns the request info stored in ctx, if any
+func FromContext(ctx context.Context) (Info, bool) {
+	if ctx == nil {
+		return Info{}, false
+	}
+	info, ok := ctx.Value(contextKey{}).(*Info)
+	if !ok {
+		return Info{}, false
+	}
+	return *info, true
+}
+
+// ResolveID returns the incoming ID when present, otherwise a new one
+func ResolveID(incoming string) string {
+	if incoming != "" && len(incoming) <= 128 {
+		return incoming
+	}
+	return NewID()
+}
+
+// ResolveActor returns the actor header value or AnonymousActor
+func ResolveActor(header string) string {
+	if actor := strings.TrimSpace(header); actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
+
+// NewID generates a random 32 character hex request ID
+func NewID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
@@ -2,11 +2,15 @@ package main
 
 import (
 	"book-management-api/domain/usecase"
+	"book-management-api/internal/audit"
 	"book-management-api/internal/logger"
+	"book-management-api/internal/parser"
+	"book-management-api/internal/requestctx"
 	"book-management-api/protocol/echo/controller"
 	"book-management-api/protocol/echo/routes"
 	echo_validator "book-management-api/protocol/echo/validator"
 	"fmt"
+	"log"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
@@ -14,21 +18,47 @@ import (
 
 func main() {
 	// Create Logger
-	loggerInstance := logger.NewAsyncLogger()
+	loggerInstance, logBuffer, err := logger.ConfigFromEnv().Build()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
 	defer loggerInstance.Close() // Ensure logger is properly closed
 
+	// Create audit trail
+	auditTrail, err := audit.NewFileTrail(audit.PathFromEnv())
+	if err != nil {
+		log.Fatalf("Failed to open audit trail: %v", err)
+	}
+	defer auditTrail.Close()
+
+	// Proxies trusted to report the client IP
+	trustedProxies, err := requestctx.TrustedProxiesFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to read trusted proxies: %v", err)
+	}
+
+	// Token guarding the /admin endpoints
+	adminToken := requestctx.AdminTokenFromEnv()
+
 	// Create Echo instance
 	e := echo.New()
+	e.IPExtractor = trustedProxies.ClientIP
 	e.Validator = &echo_validator.EchoValidator{Validator: validator.New()}
 
 	// Usecases
-	bookUsecase := usecase.NewBookUsecase(loggerInstance)
+	bookUsecase := usecase.NewBookUsecase(loggerInstance, auditTrail)
+
+	// Date parser shared by the controllers
+	dateParser := parser.NewDateTimeParser()
 
 	// Controllers
-	bookController := controller.NewBookController(bookUsecase)
+	bookController := controller.NewBookController(bookUsecase, dateParser)
+	adminController := controller.NewAdminController(logBuffer, auditTrail, dateParser)
 
 	// Routes
+	routes.Middleware(e, loggerInstance)
 	routes.BookRoutes(e, bookController)
+	routes.AdminRoutes(e, adminController, adminToken)
 
 	// Start server
 	port := ":8080"
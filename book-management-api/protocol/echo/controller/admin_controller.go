@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"book-management-api/domain/dto"
+	"book-management-api/internal/audit"
+	"book-management-api/internal/logger"
+	"book-management-api/internal/parser"
+	"book-management-api/protocol/echo/response"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AdminController struct {
+	logs  *logger.RingBufferSink
+	audit audit.Trail
+	dates *parser.DateTimeParser
+}
+
+func NewAdminController(
+	logs *logger.RingBufferSink,
+	auditTrail audit.Trail,
+	dateParser *parser.DateTimeParser,
+) *AdminController {
+	return &AdminController{
+		logs:  logs,
+		audit: auditTrail,
+		dates: dateParser,
+	}
+}
+
+// GetLogs returns the recent in-memory log entries, optionally since a given time
+func (c *AdminController) GetLogs(ctx echo.Context) error {
+	var request dto.LogsRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, http.StatusBadRequest, errors.New("Invalid query parameters"))
+	}
+
+	since, err := c.parseOptionalDate(request.Since)
+	if err != nil {
+		return response.Error(ctx, http.StatusBadRequest, err)
+	}
+
+	entries := c.logs.Since(since)
+	result := make([]dto.LogEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, dto.LogEntryResponse{
+			Time:      entry.Time,
+			Level:     string(entry.Level),
+			Message:   entry.Message,
+			RequestID: entry.RequestID,
+			Method:    entry.Method,
+			Route:     entry.Route,
+		})
+	}
+
+	return response.Success(ctx, http.StatusOK, result)
+}
+
+// GetAudit returns audit records within the optional from/to range together
+// with the result of verifying the hash chain
+func (c *AdminController) GetAudit(ctx echo.Context) error {
+	var request dto.AuditRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, http.StatusBadRequest, errors.New("Invalid query parameters"))
+	}
+
+	from, err := c.parseOptionalDate(request.From)
+	if err != nil {
+		return response.Error(ctx, http.StatusBadRequest, err)
+	}
+	to, err := c.parseOptionalDate(request.To)
+	if err != nil {
+		return response.Error(ctx, http.StatusBadRequest, err)
+	}
+
+	records, err := c.audit.Query(from, to)
+	if err != nil {
+		return response.Error(ctx, http.StatusInternalServerError, err)
+	}
+
+	result := dto.AuditTrailResponse{
+		Records:    records,
+		ChainValid: true,
+	}
+	if err := c.audit.Verify(); err != nil {
+		result.ChainValid = false
+		result.ChainError = err.Error()
+	}
+
+	return response.Success(ctx, http.StatusOK, result)
+}
+
+func (c *AdminController) parseOptionalDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return c.dates.Parse(value)
+}
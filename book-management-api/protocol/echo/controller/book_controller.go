@@ -9,19 +9,23 @@ import (
 	echo_validator "book-management-api/protocol/echo/validator"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
 type BookController struct {
 	usecase usecase.IBookUsecase
+	dates   *parser.DateTimeParser
 }
 
 func NewBookController(
 	bookUsecase usecase.IBookUsecase,
+	dateParser *parser.DateTimeParser,
 ) *BookController {
 	return &BookController{
 		usecase: bookUsecase,
+		dates:   dateParser,
 	}
 }
 
@@ -32,12 +36,22 @@ func (c *BookController) GetBooks(ctx echo.Context) error {
 		return response.Error(ctx, http.StatusBadRequest, errors.New("Invalid pagination parameters"))
 	}
 
-	result, err := c.usecase.GetBooks(pagination)
+	dateFormat, err := c.dateFormat(ctx)
 	if err != nil {
 		return response.Error(ctx, http.StatusBadRequest, err)
 	}
 
-	return response.Success(ctx, http.StatusOK, result)
+	filter, err := c.bookFilter(ctx)
+	if err != nil {
+		return response.Error(ctx, http.StatusBadRequest, err)
+	}
+
+	result, err := c.usecase.GetBooks(ctx.Request().Context(), pagination, filter)
+	if err != nil {
+		return response.Error(ctx, http.StatusBadRequest, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, dto.NewBookPage(result, dateFormat))
 }
 
 func (c *BookController) GetBookByISBN(ctx echo.Context) error {
@@ -46,12 +60,17 @@ func (c *BookController) GetBookByISBN(ctx echo.Context) error {
 		return response.Error(ctx, http.StatusBadRequest, errors.New("Invalid isbn"))
 	}
 
-	result, err := c.usecase.GetBookByISBN(params.ISBN)
+	dateFormat, err := c.dateFormat(ctx)
+	if err != nil {
+		return response.Error(ctx, http.StatusBadRequest, err)
+	}
+
+	result, err := c.usecase.GetBookByISBN(ctx.Request().Context(), params.ISBN)
 	if err != nil {
 		return response.Error(ctx, http.StatusBadRequest, err)
 	}
 
-	return response.Success(ctx, http.StatusOK, result)
+	return response.Success(ctx, http.StatusOK, dto.NewBookResponse(*result, dateFormat))
 }
 
 func (c *BookController) CreateBook(ctx echo.Context) error {
@@ -61,29 +80,43 @@ func (c *BookController) CreateBook(ctx echo.Context) error {
 		return response.Error(ctx, http.StatusBadRequest, errors.New("Invalid request"))
 	}
 
-	releaseDate, err := parser.ParseDate(bookDto.ReleaseDate)
+	dateFormat, err := c.dateFormat(ctx)
 	if err != nil {
 		return response.Error(ctx, http.StatusBadRequest, err)
 	}
 
+	dateOptions, err := c.dateOptions(ctx)
+	if err != nil {
+		return response.Error(ctx, http.StatusBadRequest, err)
+	}
+
+	releaseDate, err := c.dates.ParseDetailedWithOptions(bookDto.ReleaseDate, dateOptions)
+	if err != nil {
+		return response.Error(ctx, http.StatusBadRequest, err)
+	}
+	if releaseDate.TimeOnly {
+		return response.Error(ctx, http.StatusBadRequest, errors.New("Release date must include a date, not only a time"))
+	}
+	if releaseDate.Ambiguity != nil {
+		ctx.Response().Header().Set(parser.DateWarningHeader, releaseDate.Ambiguity.Warning())
+	}
+
 	bookEntity := entity.Book{
 		Title:       bookDto.Title,
 		Author:      bookDto.Author,
 		ISBN:        bookDto.ISBN,
-		ReleaseDate: releaseDate,
+		ReleaseDate: releaseDate.Time,
+	}
+	if releaseDate.Calendar != nil {
+		bookEntity.OriginalReleaseDate = releaseDate.Calendar.String()
 	}
 
-	result, err := c.usecase.CreateBook(bookEntity)
+	result, err := c.usecase.CreateBook(ctx.Request().Context(), bookEntity)
 	if err != nil {
 		return response.Error(ctx, http.StatusBadRequest, err)
 	}
 
-	resultResponse := dto.BookResponse{
-		Title:       result.Title,
-		Author:      result.Author,
-		ISBN:        result.ISBN,
-		ReleaseDate: result.ReleaseDate,
-	}
+	resultResponse := dto.NewBookResponse(*result, dateFormat)
 
 	return response.Success(ctx, http.StatusCreated, resultResponse)
 }
@@ -95,29 +128,43 @@ func (c *BookController) UpdateBookByISBN(ctx echo.Context) error {
 		return response.Error(ctx, http.StatusBadRequest, errors.New("Invalid request"))
 	}
 
-	releaseDate, err := parser.ParseDate(bookDto.ReleaseDate)
+	dateFormat, err := c.dateFormat(ctx)
 	if err != nil {
 		return response.Error(ctx, http.StatusBadRequest, err)
 	}
 
+	dateOptions, err := c.dateOptions(ctx)
+	if err != nil {
+		return response.Error(ctx, http.StatusBadRequest, err)
+	}
+
+	releaseDate, err := c.dates.ParseDetailedWithOptions(bookDto.ReleaseDate, dateOptions)
+	if err != nil {
+		return response.Error(ctx, http.StatusBadRequest, err)
+	}
+	if releaseDate.TimeOnly {
+		return response.Error(ctx, http.StatusBadRequest, errors.New("Release date must include a date, not only a time"))
+	}
+	if releaseDate.Ambiguity != nil {
+		ctx.Response().Header().Set(parser.DateWarningHeader, releaseDate.Ambiguity.Warning())
+	}
+
 	bookEntity := entity.Book{
 		Title:       bookDto.Title,
 		Author:      bookDto.Author,
 		ISBN:        bookDto.ISBN,
-		ReleaseDate: releaseDate,
+		ReleaseDate: releaseDate.Time,
+	}
+	if releaseDate.Calendar != nil {
+		bookEntity.OriginalReleaseDate = releaseDate.Calendar.String()
 	}
 
-	result, err := c.usecase.UpdateBook(bookEntity)
+	result, err := c.usecase.UpdateBook(ctx.Request().Context(), bookEntity)
 	if err != nil {
 		return response.Error(ctx, http.StatusBadRequest, err)
 	}
 
-	resultResponse := dto.BookResponse{
-		Title:       result.Title,
-		Author:      result.Author,
-		ISBN:        result.ISBN,
-		ReleaseDate: result.ReleaseDate,
-	}
+	resultResponse := dto.NewBookResponse(*result, dateFormat)
 
 	return response.Success(ctx, http.StatusOK, resultResponse)
 }
@@ -129,17 +176,72 @@ func (c *BookController) DeleteBookByISBN(ctx echo.Context) error {
 		return response.Error(ctx, http.StatusBadRequest, errors.New("Invalid isbn"))
 	}
 
-	result, err := c.usecase.DeleteBookByISBN(params.ISBN)
+	dateFormat, err := c.dateFormat(ctx)
 	if err != nil {
 		return response.Error(ctx, http.StatusBadRequest, err)
 	}
 
-	resultResponse := dto.BookResponse{
-		Title:       result.Title,
-		Author:      result.Author,
-		ISBN:        result.ISBN,
-		ReleaseDate: result.ReleaseDate,
+	result, err := c.usecase.DeleteBookByISBN(ctx.Request().Context(), params.ISBN)
+	if err != nil {
+		return response.Error(ctx, http.StatusBadRequest, err)
 	}
 
+	resultResponse := dto.NewBookResponse(*result, dateFormat)
+
 	return response.Success(ctx, http.StatusOK, resultResponse)
 }
+
+// bookFilter reads the released, released_after and released_before query
+// parameters. released takes an interval such as "2020-01-01/P1M",
+// "1990-1995" or "Jan–Mar 2021". The other two accept any release date
+// format, including periods such as "2021", "Q3 2021" or "last month":
+// released_after keeps books released from the start of the period,
+// released_before those released before its end.
+func (c *BookController) bookFilter(ctx echo.Context) (dto.BookFilter, error) {
+	var request dto.BookFilterRequest
+	if err := ctx.Bind(&request); err != nil {
+		return dto.BookFilter{}, errors.New("Invalid query parameters")
+	}
+
+	dateOptions, err := c.dateOptions(ctx)
+	if err != nil {
+		return dto.BookFilter{}, err
+	}
+
+	var filter dto.BookFilter
+	if request.Released != "" {
+		released, err := c.dates.ParseIntervalWithOptions(request.Released, dateOptions)
+		if err != nil {
+			return dto.BookFilter{}, err
+		}
+		filter.Narrow(released.Start, released.End)
+	}
+	if request.ReleasedAfter != "" {
+		after, err := c.dates.ParseIntervalWithOptions(request.ReleasedAfter, dateOptions)
+		if err != nil {
+			return dto.BookFilter{}, err
+		}
+		filter.Narrow(after.Start, time.Time{})
+	}
+	if request.ReleasedBefore != "" {
+		before, err := c.dates.ParseIntervalWithOptions(request.ReleasedBefore, dateOptions)
+		if err != nil {
+			return dto.BookFilter{}, err
+		}
+		filter.Narrow(time.Time{}, before.End)
+	}
+	return filter, nil
+}
+
+// dateFormat returns the parser's output format overridden by the
+// date_format query parameter
+func (c *BookController) dateFormat(ctx echo.Context) (parser.DateFormat, error) {
+	return c.dates.DateFormat().WithParam(ctx.QueryParam(parser.DateFormatParam))
+}
+
+// dateOptions returns the parser's options overridden by the X-Date-Order,
+// X-Date-Strict and Accept-Language request headers
+func (c *BookController) dateOptions(ctx echo.Context) (parser.ParseOptions, error) {
+	header := ctx.Request().Header
+	return c.dates.Options().WithHeaders(header.Get(parser.DateOrderHeader), header.Get(parser.DateStrictHeader), header.Get(parser.AcceptLanguageHeader))
+}
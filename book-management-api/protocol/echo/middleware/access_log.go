@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"book-management-api/internal/logger"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AccessLog logs one line per request using the same schema as the
+// net/http server's access log
+func AccessLog(log logger.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			err := next(c)
+			if err != nil {
+				// Let the error handler write the response so its status is logged
+				c.Error(err)
+			}
+
+			req := c.Request()
+			res := c.Response()
+
+			bytesIn := req.ContentLength
+			if bytesIn < 0 {
+				bytesIn = 0
+			}
+
+			entry := logger.AccessEntry{
+				RemoteIP:  c.RealIP(),
+				URI:       req.RequestURI,
+				Status:    res.Status,
+				Latency:   time.Since(start),
+				BytesIn:   bytesIn,
+				BytesOut:  res.Size,
+				UserAgent: req.UserAgent(),
+			}
+			if err != nil {
+				entry.Error = err.Error()
+			}
+			logger.LogAccess(req.Context(), log, entry)
+
+			return nil
+		}
+	}
+}
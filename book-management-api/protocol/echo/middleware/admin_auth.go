@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"book-management-api/internal/requestctx"
+	"book-management-api/protocol/echo/response"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminAuth rejects requests without the admin bearer token: 401 for a
+// missing or wrong token, 403 when no token is configured
+func AdminAuth(token requestctx.AdminToken) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err := token.Check(c.Request().Header.Get(echo.HeaderAuthorization)); err != nil {
+				status := http.StatusForbidden
+				if errors.Is(err, requestctx.ErrAdminUnauthorized) {
+					status = http.StatusUnauthorized
+					c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="admin"`)
+				}
+				return response.Error(c, status, err)
+			}
+
+			return next(c)
+		}
+	}
+}
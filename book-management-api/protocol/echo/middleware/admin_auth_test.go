@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"book-management-api/internal/requestctx"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         requestctx.AdminToken
+		authorization string
+		status        int
+	}{
+		{"disabled without a token", "", "Bearer s3cret", http.StatusForbidden},
+		{"missing header", "s3cret", "", http.StatusUnauthorized},
+		{"wrong token", "s3cret", "Bearer guess", http.StatusUnauthorized},
+		{"wrong scheme", "s3cret", "Basic s3cret", http.StatusUnauthorized},
+		{"valid token", "s3cret", "Bearer s3cret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			admin := e.Group("/admin", AdminAuth(tt.token))
+			admin.GET("/audit", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/audit", nil)
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status %d, want %d", rec.Code, tt.status)
+			}
+			if challenged := rec.Header().Get(echo.HeaderWWWAuthenticate) != ""; challenged != (tt.status == http.StatusUnauthorized) {
+				t.Errorf("WWW-Authenticate = %q for status %d", rec.Header().Get(echo.HeaderWWWAuthenticate), tt.status)
+			}
+		})
+	}
+}
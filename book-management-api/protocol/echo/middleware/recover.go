@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"book-management-api/internal/logger"
+	"book-management-api/protocol/echo/response"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Recover turns a panic in a handler into a 500 response and an error log,
+// like the net/http server's recover middleware
+func Recover(log logger.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					if r == http.ErrAbortHandler {
+						panic(r)
+					}
+					log.ErrorContext(c.Request().Context(), fmt.Sprintf("[PANIC RECOVER] %v %s", r, debug.Stack()))
+					err = response.Error(c, http.StatusInternalServerError, errors.New(http.StatusText(http.StatusInternalServerError)))
+				}
+			}()
+
+			return next(c)
+		}
+	}
+}
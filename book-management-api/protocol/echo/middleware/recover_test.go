@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"book-management-api/internal/requestctx"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// errorLog records the messages logged at error level with their context
+type errorLog struct {
+	messages []string
+	contexts []context.Context
+}
+
+func (l *errorLog) Info(msg string)                              {}
+func (l *errorLog) Error(msg string)                             { l.ErrorContext(context.Background(), msg) }
+func (l *errorLog) Debug(msg string)                             {}
+func (l *errorLog) InfoContext(ctx context.Context, msg string)  {}
+func (l *errorLog) DebugContext(ctx context.Context, msg string) {}
+func (l *errorLog) ErrorContext(ctx context.Context, msg string) {
+	l.messages = append(l.messages, msg)
+	l.contexts = append(l.contexts, ctx)
+}
+
+func TestRecover(t *testing.T) {
+	log := &errorLog{}
+	e := echo.New()
+	e.Use(RequestID(), Recover(log))
+	e.GET("/books/:isbn", func(c echo.Context) error {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/books/0441013597", nil)
+	req.Header.Set(requestctx.IDHeader, "req-42")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status %d, want 500", rec.Code)
+	}
+	if len(log.messages) != 1 || !strings.HasPrefix(log.messages[0], "[PANIC RECOVER] boom") {
+		t.Fatalf("logged %q, want one panic message", log.messages)
+	}
+	info, ok := requestctx.FromContext(log.contexts[0])
+	if !ok || info.ID != "req-42" || info.Route != "/books/:isbn" {
+		t.Errorf("logged with request info %+v, want ID req-42 and route /books/:isbn", info)
+	}
+}
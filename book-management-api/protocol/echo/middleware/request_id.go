@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"book-management-api/internal/requestctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestID accepts or generates an X-Request-ID, echoes it in the response
+// and stores it with the method, route, client IP and actor in the request
+// context
+func RequestID() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			id := requestctx.ResolveID(req.Header.Get(requestctx.IDHeader))
+
+			// Set on the request too so middleware.Logger() picks up ${id}
+			req.Header.Set(requestctx.IDHeader, id)
+			c.Response().Header().Set(requestctx.IDHeader, id)
+
+			ctx := requestctx.WithInfo(req.Context(), requestctx.Info{
+				ID:       id,
+				Method:   req.Method,
+				Route:    c.Path(),
+				ClientIP: c.RealIP(),
+				Actor:    requestctx.ResolveActor(req.Header.Get(requestctx.ActorHeader)),
+			})
+			c.SetRequest(req.WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
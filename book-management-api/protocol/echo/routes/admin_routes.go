@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"book-management-api/internal/requestctx"
+	"book-management-api/protocol/echo/controller"
+	echo_middleware "book-management-api/protocol/echo/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminRoutes registers the /admin endpoints behind the admin token
+func AdminRoutes(e *echo.Echo, ctrl *controller.AdminController, token requestctx.AdminToken) {
+	admin := e.Group("/admin", echo_middleware.AdminAuth(token))
+	admin.GET("/logs", ctrl.GetLogs)
+	admin.GET("/audit", ctrl.GetAudit)
+}
@@ -4,15 +4,10 @@ import (
 	"book-management-api/protocol/echo/controller"
 
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
 )
 
 func BookRoutes(e *echo.Echo, ctrl *controller.BookController) {
 
-	// Middleware
-	e.Use(middleware.Logger())
-	e.Use(middleware.Recover())
-
 	// Routes
 	e.POST("/books", ctrl.CreateBook)
 	e.GET("/books", ctrl.GetBooks)
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"book-management-api/internal/logger"
+	echo_middleware "book-management-api/protocol/echo/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func Middleware(e *echo.Echo, log logger.Logger) {
+	e.Use(echo_middleware.RequestID())
+	e.Use(echo_middleware.AccessLog(log))
+	e.Use(echo_middleware.Recover(log))
+}
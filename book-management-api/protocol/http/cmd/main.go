@@ -2,8 +2,12 @@ package main
 
 import (
 	"book-management-api/domain/usecase"
+	"book-management-api/internal/audit"
 	"book-management-api/internal/logger"
+	"book-management-api/internal/parser"
+	"book-management-api/internal/requestctx"
 	"book-management-api/protocol/http/handler"
+	"book-management-api/protocol/http/middleware"
 	"book-management-api/protocol/http/routes"
 	"log"
 	"net/http"
@@ -13,23 +17,51 @@ func main() {
 	// Initialize dependencies in correct order
 
 	// 1. Create Logger (lowest level dependency)
-	loggerInstance := logger.NewAsyncLogger()
+	loggerInstance, logBuffer, err := logger.ConfigFromEnv().Build()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
 	defer loggerInstance.Close()
 
-	// 2. Create Use Cases (business logic layer)
-	bookUsecase := usecase.NewBookUsecase(loggerInstance)
+	// 2. Create audit trail (append-only record of book mutations)
+	auditTrail, err := audit.NewFileTrail(audit.PathFromEnv())
+	if err != nil {
+		log.Fatalf("Failed to open audit trail: %v", err)
+	}
+	defer auditTrail.Close()
 
-	// 3. Create Handlers (presentation layer)
-	bookHandler := handler.NewBookHandler(bookUsecase)
+	// 3. Read the proxies trusted to report the client IP and the admin token
+	trustedProxies, err := requestctx.TrustedProxiesFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to read trusted proxies: %v", err)
+	}
+	adminToken := requestctx.AdminTokenFromEnv()
 
-	// 4. Create Router with injected handler
+	// 4. Create Use Cases (business logic layer)
+	bookUsecase := usecase.NewBookUsecase(loggerInstance, auditTrail)
+
+	// 5. Create the date parser shared by the handlers
+	dateParser := parser.NewDateTimeParser()
+
+	// 6. Create Handlers (presentation layer)
+	bookHandler := handler.NewBookHandler(bookUsecase, dateParser)
+	adminHandler := handler.NewAdminHandler(logBuffer, auditTrail, dateParser)
+
+	// 7. Create Routers with injected handlers
 	bookRouter := routes.NewBookRouter(bookHandler)
+	adminRouter := routes.NewAdminRouter(adminHandler)
 
-	// 5. Setup HTTP routes
+	// 8. Setup HTTP routes
 	http.HandleFunc("/books", bookRouter.Routes)
 	http.HandleFunc("/books/", bookRouter.Routes) // Handle paths with ISBN
+	http.Handle("/admin/", middleware.AdminAuth(adminToken)(http.HandlerFunc(adminRouter.Routes)))
 
 	port := ":8080"
 	loggerInstance.Info("Server is running on port " + port)
-	log.Fatal(http.ListenAndServe(port, nil))
+	server := middleware.Chain(http.DefaultServeMux,
+		middleware.RequestID(trustedProxies, routes.Pattern),
+		middleware.AccessLog(loggerInstance),
+		middleware.Recover(loggerInstance),
+	)
+	log.Fatal(http.ListenAndServe(port, server))
 }
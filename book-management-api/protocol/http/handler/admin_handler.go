@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"book-management-api/domain/dto"
+	"book-management-api/internal/audit"
+	"book-management-api/internal/logger"
+	"book-management-api/internal/parser"
+	"book-management-api/protocol/http/response"
+	"net/http"
+	"time"
+)
+
+type AdminHandler struct {
+	logs  *logger.RingBufferSink
+	audit audit.Trail
+	dates *parser.DateTimeParser
+}
+
+func NewAdminHandler(logs *logger.RingBufferSink, auditTrail audit.Trail, dateParser *parser.DateTimeParser) *AdminHandler {
+	return &AdminHandler{
+		logs:  logs,
+		audit: auditTrail,
+		dates: dateParser,
+	}
+}
+
+// GetLogs handles GET /admin/logs?since=...
+func (h *AdminHandler) GetLogs(w http.ResponseWriter, r *http.Request) {
+	since, err := h.parseOptionalDate(r.URL.Query().Get("since"))
+	if err != nil {
+		response.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries := h.logs.Since(since)
+	result := make([]dto.LogEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, dto.LogEntryResponse{
+			Time:      entry.Time,
+			Level:     string(entry.Level),
+			Message:   entry.Message,
+			RequestID: entry.RequestID,
+			Method:    entry.Method,
+			Route:     entry.Route,
+		})
+	}
+
+	response.SendJSONResponse(w, result, http.StatusOK)
+}
+
+// GetAudit handles GET /admin/audit?from=...&to=...
+func (h *AdminHandler) GetAudit(w http.ResponseWriter, r *http.Request) {
+	from, err := h.parseOptionalDate(r.URL.Query().Get("from"))
+	if err != nil {
+		response.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	to, err := h.parseOptionalDate(r.URL.Query().Get("to"))
+	if err != nil {
+		response.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	records, err := h.audit.Query(from, to)
+	if err != nil {
+		response.SendErrorResponse(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := dto.AuditTrailResponse{
+		Records:    records,
+		ChainValid: true,
+	}
+	if err := h.audit.Verify(); err != nil {
+		result.ChainValid = false
+		result.ChainError = err.Error()
+	}
+
+	response.SendJSONResponse(w, result, http.StatusOK)
+}
+
+// Helper function to parse an optional date query parameter
+func (h *AdminHandler) parseOptionalDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return h.dates.Parse(value)
+}
@@ -11,15 +11,18 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type BookHandler struct {
 	usecase usecase.IBookUsecase
+	dates   *parser.DateTimeParser
 }
 
-func NewBookHandler(bookUsecase usecase.IBookUsecase) *BookHandler {
+func NewBookHandler(bookUsecase usecase.IBookUsecase, dateParser *parser.DateTimeParser) *BookHandler {
 	return &BookHandler{
 		usecase: bookUsecase,
+		dates:   dateParser,
 	}
 }
 
@@ -53,13 +56,25 @@ func (h *BookHandler) GetBooks(w http.ResponseWriter, r *http.Request) {
 		SortOrder: sortOrder,
 	}
 
-	paginatedResponse, err := h.usecase.GetBooks(paginationReq)
+	dateFormat, err := h.dateFormat(r)
+	if err != nil {
+		response.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter, err := h.bookFilter(r)
+	if err != nil {
+		response.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	paginatedResponse, err := h.usecase.GetBooks(r.Context(), paginationReq, filter)
 	if err != nil {
 		response.SendErrorResponse(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	response.SendJSONResponse(w, paginatedResponse, http.StatusOK)
+	response.SendJSONResponse(w, dto.NewBookPage(paginatedResponse, dateFormat), http.StatusOK)
 }
 
 // GetBookByISBNHandler handles GET /books/{isbn}
@@ -70,13 +85,19 @@ func (h *BookHandler) GetBookByISBN(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	book, err := h.usecase.GetBookByISBN(isbn)
+	dateFormat, err := h.dateFormat(r)
+	if err != nil {
+		response.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	book, err := h.usecase.GetBookByISBN(r.Context(), isbn)
 	if err != nil {
 		response.SendErrorResponse(w, err.Error(), http.StatusNotFound)
 		return
 	}
 
-	response.SendJSONResponse(w, book, http.StatusOK)
+	response.SendJSONResponse(w, dto.NewBookResponse(*book, dateFormat), http.StatusOK)
 }
 
 // CreateBookHandler handles POST /books
@@ -87,16 +108,39 @@ func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	releaseDate, err := parser.ParseDate(bookDto.ReleaseDate)
+	dateFormat, err := h.dateFormat(r)
 	if err != nil {
 		response.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	dateOptions, err := h.dateOptions(r)
+	if err != nil {
+		response.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	releaseDate, err := h.dates.ParseDetailedWithOptions(bookDto.ReleaseDate, dateOptions)
+	if err != nil {
+		response.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if releaseDate.TimeOnly {
+		response.SendErrorResponse(w, "Release date must include a date, not only a time", http.StatusBadRequest)
+		return
+	}
+	if releaseDate.Ambiguity != nil {
+		w.Header().Set(parser.DateWarningHeader, releaseDate.Ambiguity.Warning())
 	}
 
 	bookEntity := entity.Book{
 		Title:       bookDto.Title,
 		Author:      bookDto.Author,
 		ISBN:        bookDto.ISBN,
-		ReleaseDate: releaseDate,
+		ReleaseDate: releaseDate.Time,
+	}
+	if releaseDate.Calendar != nil {
+		bookEntity.OriginalReleaseDate = releaseDate.Calendar.String()
 	}
 
 	if err = validator.ValidateBook(bookEntity); err != nil {
@@ -104,13 +148,13 @@ func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	createdBook, err := h.usecase.CreateBook(bookEntity)
+	createdBook, err := h.usecase.CreateBook(r.Context(), bookEntity)
 	if err != nil {
 		response.SendErrorResponse(w, err.Error(), http.StatusConflict)
 		return
 	}
 
-	response.SendJSONResponse(w, createdBook, http.StatusCreated)
+	response.SendJSONResponse(w, dto.NewBookResponse(*createdBook, dateFormat), http.StatusCreated)
 }
 
 // UpdateBookHandler handles PUT /books/{isbn}
@@ -127,16 +171,39 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	releaseDate, err := parser.ParseDate(bookDto.ReleaseDate)
+	dateFormat, err := h.dateFormat(r)
 	if err != nil {
 		response.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	dateOptions, err := h.dateOptions(r)
+	if err != nil {
+		response.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	releaseDate, err := h.dates.ParseDetailedWithOptions(bookDto.ReleaseDate, dateOptions)
+	if err != nil {
+		response.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if releaseDate.TimeOnly {
+		response.SendErrorResponse(w, "Release date must include a date, not only a time", http.StatusBadRequest)
+		return
+	}
+	if releaseDate.Ambiguity != nil {
+		w.Header().Set(parser.DateWarningHeader, releaseDate.Ambiguity.Warning())
 	}
 
 	bookEntity := entity.Book{
 		Title:       bookDto.Title,
 		Author:      bookDto.Author,
 		ISBN:        isbn,
-		ReleaseDate: releaseDate,
+		ReleaseDate: releaseDate.Time,
+	}
+	if releaseDate.Calendar != nil {
+		bookEntity.OriginalReleaseDate = releaseDate.Calendar.String()
 	}
 
 	if err = validator.ValidateBook(bookEntity); err != nil {
@@ -144,7 +211,7 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	updatedBook, err := h.usecase.UpdateBook(bookEntity)
+	updatedBook, err := h.usecase.UpdateBook(r.Context(), bookEntity)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			response.SendErrorResponse(w, err.Error(), http.StatusNotFound)
@@ -154,7 +221,7 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.SendJSONResponse(w, updatedBook, http.StatusOK)
+	response.SendJSONResponse(w, dto.NewBookResponse(*updatedBook, dateFormat), http.StatusOK)
 }
 
 // DeleteBookHandler handles DELETE /books/{isbn}
@@ -165,13 +232,19 @@ func (h *BookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	book, err := h.usecase.DeleteBookByISBN(isbn)
+	dateFormat, err := h.dateFormat(r)
+	if err != nil {
+		response.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	book, err := h.usecase.DeleteBookByISBN(r.Context(), isbn)
 	if err != nil {
 		response.SendErrorResponse(w, err.Error(), http.StatusNotFound)
 		return
 	}
 
-	response.SendJSONResponse(w, book, http.StatusOK)
+	response.SendJSONResponse(w, dto.NewBookResponse(*book, dateFormat), http.StatusOK)
 }
 
 // Helper method to extract ISBN from URL path
@@ -182,3 +255,52 @@ func (h *BookHandler) extractISBNFromPath(path string) string {
 	}
 	return ""
 }
+
+// bookFilter reads the released, released_after and released_before query
+// parameters. released takes an interval such as "2020-01-01/P1M",
+// "1990-1995" or "Jan–Mar 2021". The other two accept any release date
+// format, including periods such as "2021", "Q3 2021" or "last month":
+// released_after keeps books released from the start of the period,
+// released_before those released before its end.
+func (h *BookHandler) bookFilter(r *http.Request) (dto.BookFilter, error) {
+	dateOptions, err := h.dateOptions(r)
+	if err != nil {
+		return dto.BookFilter{}, err
+	}
+
+	var filter dto.BookFilter
+	if value := r.URL.Query().Get("released"); value != "" {
+		released, err := h.dates.ParseIntervalWithOptions(value, dateOptions)
+		if err != nil {
+			return dto.BookFilter{}, err
+		}
+		filter.Narrow(released.Start, released.End)
+	}
+	if value := r.URL.Query().Get("released_after"); value != "" {
+		after, err := h.dates.ParseIntervalWithOptions(value, dateOptions)
+		if err != nil {
+			return dto.BookFilter{}, err
+		}
+		filter.Narrow(after.Start, time.Time{})
+	}
+	if value := r.URL.Query().Get("released_before"); value != "" {
+		before, err := h.dates.ParseIntervalWithOptions(value, dateOptions)
+		if err != nil {
+			return dto.BookFilter{}, err
+		}
+		filter.Narrow(time.Time{}, before.End)
+	}
+	return filter, nil
+}
+
+// dateFormat returns the parser's output format overridden by the
+// date_format query parameter
+func (h *BookHandler) dateFormat(r *http.Request) (parser.DateFormat, error) {
+	return h.dates.DateFormat().WithParam(r.URL.Query().Get(parser.DateFormatParam))
+}
+
+// dateOptions returns the parser's options overridden by the X-Date-Order,
+// X-Date-Strict and Accept-Language request headers
+func (h *BookHandler) dateOptions(r *http.Request) (parser.ParseOptions, error) {
+	return h.dates.Options().WithHeaders(r.Header.Get(parser.DateOrderHeader), r.Header.Get(parser.DateStrictHeader), r.Header.Get(parser.AcceptLanguageHeader))
+}
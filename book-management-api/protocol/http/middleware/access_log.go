@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"book-management-api/internal/logger"
+	"book-management-api/internal/requestctx"
+	"net/http"
+	"time"
+)
+
+// AccessLog logs one line per request with status, latency and response size
+func AccessLog(log logger.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := newResponseRecorder(w)
+
+			next.ServeHTTP(recorder, r)
+
+			bytesIn := r.ContentLength
+			if bytesIn < 0 {
+				bytesIn = 0
+			}
+
+			// RequestID has resolved the client IP against the trusted proxies
+			remoteIP := r.RemoteAddr
+			if info, ok := requestctx.FromContext(r.Context()); ok {
+				remoteIP = info.ClientIP
+			}
+
+			logger.LogAccess(r.Context(), log, logger.AccessEntry{
+				RemoteIP:  remoteIP,
+				URI:       r.RequestURI,
+				Status:    recorder.status,
+				Latency:   time.Since(start),
+				BytesIn:   bytesIn,
+				BytesOut:  recorder.size,
+				UserAgent: r.UserAgent(),
+			})
+		})
+	}
+}
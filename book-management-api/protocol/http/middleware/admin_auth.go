@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"book-management-api/internal/requestctx"
+	"book-management-api/protocol/http/response"
+	"errors"
+	"net/http"
+)
+
+// AdminAuth rejects requests without the admin bearer token: 401 for a
+// missing or wrong token, 403 when no token is configured
+func AdminAuth(token requestctx.AdminToken) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := token.Check(r.Header.Get("Authorization")); err != nil {
+				status := http.StatusForbidden
+				if errors.Is(err, requestctx.ErrAdminUnauthorized) {
+					status = http.StatusUnauthorized
+					w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+				}
+				response.SendErrorResponse(w, err.Error(), status)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
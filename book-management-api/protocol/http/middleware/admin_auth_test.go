@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"book-management-api/internal/requestctx"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         requestctx.AdminToken
+		authorization string
+		status        int
+	}{
+		{"disabled without a token", "", "Bearer s3cret", http.StatusForbidden},
+		{"missing header", "s3cret", "", http.StatusUnauthorized},
+		{"wrong token", "s3cret", "Bearer guess", http.StatusUnauthorized},
+		{"wrong scheme", "s3cret", "Basic s3cret", http.StatusUnauthorized},
+		{"valid token", "s3cret", "Bearer s3cret", http.StatusOK},
+		{"scheme is case-insensitive", "s3cret", "bearer s3cret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached := false
+			handler := AdminAuth(tt.token)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/logs", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status %d, want %d", rec.Code, tt.status)
+			}
+			if reached != (tt.status == http.StatusOK) {
+				t.Errorf("handler reached = %v for status %d", reached, tt.status)
+			}
+			if challenged := rec.Header().Get("WWW-Authenticate") != ""; challenged != (tt.status == http.StatusUnauthorized) {
+				t.Errorf("WWW-Authenticate = %q for status %d", rec.Header().Get("WWW-Authenticate"), tt.status)
+			}
+		})
+	}
+}
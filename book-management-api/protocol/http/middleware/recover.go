@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"book-management-api/internal/logger"
+	"book-management-api/protocol/http/response"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recover turns a panic in a handler into a 500 response and an error log
+func Recover(log logger.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if err := recover(); err != nil {
+					if err == http.ErrAbortHandler {
+						panic(err)
+					}
+					log.ErrorContext(r.Context(), fmt.Sprintf("[PANIC RECOVER] %v %s", err, debug.Stack()))
+					response.SendErrorResponse(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"book-management-api/internal/requestctx"
+	"net/http"
+)
+
+// RequestID accepts or generates an X-Request-ID, echoes it in the response
+// and stores it with the method, route, client IP and actor in the request
+// context. route returns the pattern the request matches, and the client IP
+// is taken from forwarding headers only when sent by one of proxies.
+func RequestID(proxies *requestctx.TrustedProxies, route func(*http.Request) string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := requestctx.ResolveID(r.Header.Get(requestctx.IDHeader))
+			r.Header.Set(requestctx.IDHeader, id)
+			w.Header().Set(requestctx.IDHeader, id)
+
+			ctx := requestctx.WithInfo(r.Context(), requestctx.Info{
+				ID:       id,
+				Method:   r.Method,
+				Route:    route(r),
+				ClientIP: proxies.ClientIP(r),
+				Actor:    requestctx.ResolveActor(r.Header.Get(requestctx.ActorHeader)),
+			})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"book-management-api/protocol/http/handler"
+	"book-management-api/protocol/http/response"
+	"net/http"
+)
+
+// AdminRouter holds the admin handler dependencies
+type AdminRouter struct {
+	adminHandler *handler.AdminHandler
+}
+
+// NewAdminRouter creates a new router with injected dependencies
+func NewAdminRouter(adminHandler *handler.AdminHandler) *AdminRouter {
+	return &AdminRouter{
+		adminHandler: adminHandler,
+	}
+}
+
+// Routes method handles routing logic
+func (ar *AdminRouter) Routes(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/admin/logs" && r.Method == http.MethodGet:
+		ar.adminHandler.GetLogs(w, r)
+	case r.URL.Path == "/admin/audit" && r.Method == http.MethodGet:
+		ar.adminHandler.GetAudit(w, r)
+	default:
+		response.SendErrorResponse(w, "Endpoint not found", http.StatusNotFound)
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Pattern returns the route pattern a request matches, named as on the Echo
+// server so log lines and audit records agree, or "" for unknown paths
+func Pattern(r *http.Request) string {
+	path := r.URL.Path
+	switch {
+	case path == "/books":
+		return "/books"
+	case strings.HasPrefix(path, "/books/"):
+		return "/books/:isbn"
+	case path == "/admin/logs", path == "/admin/audit":
+		return path
+	default:
+		return ""
+	}
+}
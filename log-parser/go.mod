@@ -0,0 +1,3 @@
+module log-parser
+
+go 1.23.4
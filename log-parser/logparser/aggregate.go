@@ -0,0 +1,178 @@
+package logparser
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// DataLine maps each action (created, updated, deleted, ...) to the IDs it
+// was applied to within a bucket
+type DataLine map[string][]string
+
+// Actions returns the actions of the line, sorted
+func (d DataLine) Actions() []string {
+	actions := make([]string, 0, len(d))
+	for action := range d {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// ActionFilter decides which actions are aggregated. An empty allowlist
+// admits every action not on the denylist.
+type ActionFilter struct {
+	allow map[string]bool
+	deny  map[string]bool
+}
+
+// NewActionFilter builds a filter from comma-separated allow and deny lists
+func NewActionFilter(allow, deny string) *ActionFilter {
+	return &ActionFilter{allow: actionSet(allow), deny: actionSet(deny)}
+}
+
+func actionSet(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, action := range strings.Split(list, ",") {
+		if action = strings.ToLower(strings.TrimSpace(action)); action != "" {
+			set[action] = true
+		}
+	}
+	return set
+}
+
+// Allows reports whether action should be aggregated
+func (f *ActionFilter) Allows(action string) bool {
+	if f == nil {
+		return true
+	}
+	if f.deny[action] {
+		return false
+	}
+	return len(f.allow) == 0 || f.allow[action]
+}
+
+// Aggregator groups events into time buckets
+type Aggregator struct {
+	bucketer *Bucketer
+	filter   *ActionFilter
+	buckets  map[string]*Bucket
+	actions  map[string]bool
+}
+
+// NewAggregator creates an empty aggregator using bucketer for bucket keys.
+// Actions on an allowlist appear in every bucket even when never seen; other
+// actions appear once they have been seen in any bucket. A nil filter admits
+// every action.
+func NewAggregator(bucketer *Bucketer, filter *ActionFilter) *Aggregator {
+	a := &Aggregator{
+		bucketer: bucketer,
+		filter:   filter,
+		buckets:  make(map[string]*Bucket),
+		actions:  make(map[string]bool),
+	}
+	if filter != nil {
+		for action := range filter.allow {
+			if !filter.deny[action] {
+				a.actions[action] = true
+			}
+		}
+	}
+	return a
+}
+
+// Add records an event whose Time has been parsed. Events with a filtered out
+// action are ignored.
+func (a *Aggregator) Add(event Event) error {
+	if !a.filter.Allows(event.Action) {
+		return nil
+	}
+
+	timestamp := a.bucketer.Key(event.Time)
+
+	// insert timestamp if not exist in map
+	bucket, ok := a.buckets[timestamp]
+	if !ok {
+		bucket = &Bucket{
+			Key:      timestamp,
+			Start:    a.bucketer.Start(event.Time),
+			DataLine: DataLine{},
+		}
+		a.buckets[timestamp] = bucket
+	}
+
+	a.actions[event.Action] = true
+	bucket.DataLine[event.Action] = append(bucket.DataLine[event.Action], event.ID)
+	return nil
+}
+
+// Merge adds the buckets of other, which should come later in the input, so
+// IDs keep their input order within each bucket
+func (a *Aggregator) Merge(other *Aggregator) {
+	for action := range other.actions {
+		a.actions[action] = true
+	}
+
+	for key, theirs := range other.buckets {
+		ours, ok := a.buckets[key]
+		if !ok {
+			a.buckets[key] = theirs
+			continue
+		}
+		for action, ids := range theirs.DataLine {
+			ours.DataLine[action] = append(ours.DataLine[action], ids...)
+		}
+	}
+}
+
+// Actions returns the actions seen so far, sorted
+func (a *Aggregator) Actions() []string {
+	actions := make([]string, 0, len(a.actions))
+	for action := range a.actions {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// Buckets returns every bucket in chronological order
+func (a *Aggregator) Buckets() []*Bucket {
+	buckets := make([]*Bucket, 0, len(a.buckets))
+	for _, bucket := range a.buckets {
+		buckets = append(buckets, a.complete(bucket))
+	}
+	SortBuckets(buckets)
+	return buckets
+}
+
+// complete gives bucket an empty list for every known action it lacks, so all
+// buckets share the same keys
+func (a *Aggregator) complete(bucket *Bucket) *Bucket {
+	for action := range a.actions {
+		if _, ok := bucket.DataLine[action]; !ok {
+			bucket.DataLine[action] = []string{}
+		}
+	}
+	return bucket
+}
+
+// IsClosed reports whether the bucket holding t ended more than lateness before now
+func (a *Aggregator) IsClosed(t time.Time, now time.Time, lateness time.Duration) bool {
+	end := a.bucketer.End(a.bucketer.Start(t))
+	return !end.Add(lateness).After(now)
+}
+
+// TakeClosed removes and returns, in chronological order, the buckets that
+// ended more than lateness before now
+func (a *Aggregator) TakeClosed(now time.Time, lateness time.Duration) []*Bucket {
+	var closed []*Bucket
+	for key, bucket := range a.buckets {
+		if !a.bucketer.End(bucket.Start).Add(lateness).After(now) {
+			closed = append(closed, a.complete(bucket))
+			delete(a.buckets, key)
+		}
+	}
+	SortBuckets(closed)
+	return closed
+}
@@ -0,0 +1,153 @@
+package logparser
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var jakarta = time.FixedZone("WIB", 7*60*60)
+
+func event(action, id string, minute, second int) Event {
+	return Event{Action: action, ID: id, Time: time.Date(2025, 5, 26, 10, minute, second, 0, jakarta)}
+}
+
+func newMinuteAggregator(t *testing.T, filter *ActionFilter) *Aggregator {
+	t.Helper()
+	bucketer, err := NewBucketer("minute", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewAggregator(bucketer, filter)
+}
+
+func TestAggregatorAdd(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *ActionFilter
+		events []Event
+		want   map[string]DataLine
+	}{
+		{
+			// Regression: deleted used to be seeded from the created list, so
+			// created IDs showed up under deleted in output_example.json
+			name: "deleted does not include created ids",
+			events: []Event{
+				event("created", "a", 45, 10),
+				event("created", "b", 45, 26),
+				event("deleted", "c", 45, 41),
+			},
+			want: map[string]DataLine{
+				"2025-05-26T10:45+07:00": {"created": {"a", "b"}, "deleted": {"c"}},
+			},
+		},
+		{
+			name: "every action found appears in every bucket",
+			events: []Event{
+				event("created", "a", 45, 10),
+				event("updated", "a", 46, 0),
+			},
+			want: map[string]DataLine{
+				"2025-05-26T10:45+07:00": {"created": {"a"}, "updated": {}},
+				"2025-05-26T10:46+07:00": {"created": {}, "updated": {"a"}},
+			},
+		},
+		{
+			name:   "allowlist adds missing actions and drops others",
+			filter: NewActionFilter("created, deleted", ""),
+			events: []Event{
+				event("created", "a", 45, 10),
+				event("updated", "a", 45, 20),
+			},
+			want: map[string]DataLine{
+				"2025-05-26T10:45+07:00": {"created": {"a"}, "deleted": {}},
+			},
+		},
+		{
+			name:   "denylist",
+			filter: NewActionFilter("", "updated"),
+			events: []Event{
+				event("created", "a", 45, 10),
+				event("updated", "a", 45, 20),
+			},
+			want: map[string]DataLine{
+				"2025-05-26T10:45+07:00": {"created": {"a"}},
+			},
+		},
+		{
+			// The same wall-clock minute in two offsets is two buckets
+			name: "mixed offsets are kept apart",
+			events: []Event{
+				event("created", "a", 45, 10),
+				{Action: "created", ID: "b", Time: time.Date(2025, 5, 26, 10, 45, 20, 0, time.UTC)},
+			},
+			want: map[string]DataLine{
+				"2025-05-26T10:45+07:00": {"created": {"a"}},
+				"2025-05-26T10:45Z":      {"created": {"b"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aggregator := newMinuteAggregator(t, tt.filter)
+			for _, e := range tt.events {
+				if err := aggregator.Add(e); err != nil {
+					t.Fatalf("Add() error = %v", err)
+				}
+			}
+
+			got := make(map[string]DataLine)
+			for _, bucket := range aggregator.Buckets() {
+				got[bucket.Key] = bucket.DataLine
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Buckets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregatorMergeKeepsInputOrder(t *testing.T) {
+	first := newMinuteAggregator(t, nil)
+	second := newMinuteAggregator(t, nil)
+	first.Add(event("created", "a", 45, 50))
+	second.Add(event("created", "b", 45, 10))
+	second.Add(event("deleted", "c", 46, 0))
+
+	first.Merge(second)
+
+	buckets := first.Buckets()
+	if len(buckets) != 2 {
+		t.Fatalf("Buckets() = %d buckets, want 2", len(buckets))
+	}
+	if got := buckets[0].DataLine["created"]; !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("created = %v, want [a b]", got)
+	}
+	if got := first.Actions(); !reflect.DeepEqual(got, []string{"created", "deleted"}) {
+		t.Errorf("Actions() = %v, want [created deleted]", got)
+	}
+}
+
+func TestAggregatorTakeClosed(t *testing.T) {
+	aggregator := newMinuteAggregator(t, nil)
+	aggregator.Add(event("created", "a", 45, 10))
+	aggregator.Add(event("created", "b", 46, 10))
+
+	now := time.Date(2025, 5, 26, 10, 46, 3, 0, jakarta)
+	closed := aggregator.TakeClosed(now, 5*time.Second)
+	if len(closed) != 0 {
+		t.Fatalf("TakeClosed() within lateness = %d buckets, want 0", len(closed))
+	}
+
+	closed = aggregator.TakeClosed(now.Add(2*time.Second), 5*time.Second)
+	if len(closed) != 1 || closed[0].Key != "2025-05-26T10:45+07:00" {
+		t.Fatalf("TakeClosed() = %v, want the 10:45 bucket", closed)
+	}
+	if remaining := aggregator.Buckets(); len(remaining) != 1 || remaining[0].Key != "2025-05-26T10:46+07:00" {
+		t.Errorf("Buckets() after TakeClosed = %v, want the 10:46 bucket", remaining)
+	}
+	if !aggregator.IsClosed(event("created", "c", 45, 59).Time, now.Add(2*time.Second), 5*time.Second) {
+		t.Error("IsClosed() = false for a late event in an emitted bucket")
+	}
+}
@@ -0,0 +1,95 @@
+package logparser
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Bucketer assigns times to fixed-width buckets in a timezone
+type Bucketer struct {
+	unit   string
+	size   time.Duration
+	layout string
+	loc    *time.Location
+}
+
+// NewBucketer parses a bucket spec: second, minute, hour, day or a Go
+// duration such as 5m or 90s. A nil loc keeps each timestamp's own offset.
+func NewBucketer(spec string, loc *time.Location) (*Bucketer, error) {
+	b := &Bucketer{unit: strings.ToLower(spec), loc: loc}
+
+	switch b.unit {
+	case "second":
+		b.size, b.layout = time.Second, "2006-01-02T15:04:05"
+	case "minute":
+		b.size, b.layout = time.Minute, "2006-01-02T15:04"
+	case "hour":
+		b.size, b.layout = time.Hour, "2006-01-02T15"
+	case "day":
+		b.size, b.layout = 24*time.Hour, "2006-01-02"
+	default:
+		size, err := time.ParseDuration(spec)
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("invalid bucket %q, expected second, minute, hour, day or a duration like 5m", spec)
+		}
+		b.unit, b.size = "duration", size
+		b.layout = "2006-01-02T15:04:05"
+		if size%time.Second != 0 {
+			b.layout = "2006-01-02T15:04:05.000"
+		}
+	}
+
+	// Without a timezone, buckets of different offsets are kept apart
+	if loc == nil {
+		b.layout += "Z07:00"
+	}
+
+	return b, nil
+}
+
+// In converts t to the bucketer's timezone, if it has one
+func (b *Bucketer) In(t time.Time) time.Time {
+	if b.loc != nil {
+		return t.In(b.loc)
+	}
+	return t
+}
+
+// Start returns the beginning of the bucket containing t
+func (b *Bucketer) Start(t time.Time) time.Time {
+	t = b.In(t)
+
+	year, month, day := t.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+
+	switch b.unit {
+	case "second":
+		return time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), 0, t.Location())
+	case "minute":
+		return time.Date(year, month, day, t.Hour(), t.Minute(), 0, 0, t.Location())
+	case "hour":
+		return time.Date(year, month, day, t.Hour(), 0, 0, 0, t.Location())
+	case "day":
+		return midnight
+	default:
+		// Align arbitrary durations to local midnight so 15m buckets start on
+		// the quarter hour regardless of the timezone offset
+		elapsed := t.Sub(midnight)
+		return midnight.Add(elapsed - elapsed%b.size)
+	}
+}
+
+// End returns the end (exclusive) of the bucket starting at start
+func (b *Bucketer) End(start time.Time) time.Time {
+	if b.unit == "day" {
+		return start.AddDate(0, 0, 1)
+	}
+	return start.Add(b.size)
+}
+
+// Key returns the bucket label for t, e.g. "2025-05-26T10:45" for minutes,
+// or "2025-05-26T10:45+07:00" when each timestamp keeps its own offset
+func (b *Bucketer) Key(t time.Time) string {
+	return b.Start(t).Format(b.layout)
+}
@@ -0,0 +1,86 @@
+package logparser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucketerKey(t *testing.T) {
+	instant := time.Date(2025, 5, 26, 23, 47, 31, 500_000_000, jakarta)
+
+	tests := []struct {
+		spec string
+		loc  *time.Location
+		want string
+	}{
+		{spec: "second", want: "2025-05-26T23:47:31+07:00"},
+		{spec: "minute", want: "2025-05-26T23:47+07:00"},
+		{spec: "hour", want: "2025-05-26T23+07:00"},
+		{spec: "day", want: "2025-05-26+07:00"},
+		{spec: "day", loc: time.UTC, want: "2025-05-26"},
+		{spec: "hour", loc: time.UTC, want: "2025-05-26T16"},
+		{spec: "15m", want: "2025-05-26T23:45:00+07:00"},
+		{spec: "250ms", want: "2025-05-26T23:47:31.500+07:00"},
+	}
+
+	for _, tt := range tests {
+		name := tt.spec
+		if tt.loc != nil {
+			name += " in " + tt.loc.String()
+		}
+		t.Run(name, func(t *testing.T) {
+			bucketer, err := NewBucketer(tt.spec, tt.loc)
+			if err != nil {
+				t.Fatalf("NewBucketer() error = %v", err)
+			}
+			if got := bucketer.Key(instant); got != tt.want {
+				t.Errorf("Key() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBucketerKeyMixedOffsets(t *testing.T) {
+	// 10:45 in Jakarta and in UTC are seven hours apart
+	jakartaTime := time.Date(2025, 5, 26, 10, 45, 10, 0, jakarta)
+	utcTime := time.Date(2025, 5, 26, 10, 45, 20, 0, time.UTC)
+
+	tests := []struct {
+		loc          *time.Location
+		jakarta, utc string
+	}{
+		{loc: nil, jakarta: "2025-05-26T10:45+07:00", utc: "2025-05-26T10:45Z"},
+		{loc: time.UTC, jakarta: "2025-05-26T03:45", utc: "2025-05-26T10:45"},
+		{loc: time.FixedZone("", -7*60*60), jakarta: "2025-05-25T20:45", utc: "2025-05-26T03:45"},
+	}
+
+	for _, tt := range tests {
+		bucketer, err := NewBucketer("minute", tt.loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := bucketer.Key(jakartaTime); got != tt.jakarta {
+			t.Errorf("Key(%s) in %v = %s, want %s", jakartaTime, tt.loc, got, tt.jakarta)
+		}
+		if got := bucketer.Key(utcTime); got != tt.utc {
+			t.Errorf("Key(%s) in %v = %s, want %s", utcTime, tt.loc, got, tt.utc)
+		}
+	}
+
+	// The same instant in two offsets shares a bucket once normalized
+	bucketer, err := NewBucketer("minute", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, b := bucketer.Key(utcTime), bucketer.Key(utcTime.In(jakarta)); a != b {
+		t.Errorf("Key() = %s and %s for the same instant", a, b)
+	}
+}
+
+func TestNewBucketerRejectsInvalidSpecs(t *testing.T) {
+	for _, spec := range []string{"week", "-5m", "0s", ""} {
+		if _, err := NewBucketer(spec, nil); err == nil {
+			t.Errorf("NewBucketer(%q) succeeded, want an error", spec)
+		}
+	}
+}
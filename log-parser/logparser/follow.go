@@ -0,0 +1,217 @@
+package logparser
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Tailer follows a file like tail -F: it starts at the end, reads lines as
+// they are appended and reopens the file when it is truncated or replaced by
+// log rotation (detected by a change of inode)
+type Tailer struct {
+	path    string
+	file    *os.File
+	info    os.FileInfo
+	reader  *bufio.Reader
+	offset  int64
+	partial string
+}
+
+// NewTailer opens path positioned at its current end
+func NewTailer(path string) (*Tailer, error) {
+	t := &Tailer{path: path}
+	if err := t.open(true); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Tailer) open(atEnd bool) error {
+	file, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	t.offset = 0
+	if atEnd {
+		if t.offset, err = file.Seek(0, io.SeekEnd); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	if t.file != nil {
+		t.file.Close()
+	}
+	t.file = file
+	t.info = info
+	t.reader = bufio.NewReader(file)
+	t.partial = ""
+	return nil
+}
+
+// Poll returns the complete lines appended since the last call
+func (t *Tailer) Poll() ([]string, error) {
+	lines, err := t.readLines()
+	if err != nil {
+		return lines, err
+	}
+
+	info, err := os.Stat(t.path)
+	if errors.Is(err, os.ErrNotExist) {
+		// Rotated away and not yet recreated, try again on the next poll
+		return lines, nil
+	}
+	if err != nil {
+		return lines, err
+	}
+
+	switch {
+	case !os.SameFile(info, t.info):
+		// Rotated: the old file has been drained above, read the new one from the start
+		if err := t.open(false); err != nil {
+			return lines, err
+		}
+		more, err := t.readLines()
+		return append(lines, more...), err
+	case info.Size() < t.offset:
+		// Truncated in place
+		if _, err := t.file.Seek(0, io.SeekStart); err != nil {
+			return lines, err
+		}
+		t.offset = 0
+		t.partial = ""
+		t.reader.Reset(t.file)
+	}
+
+	return lines, nil
+}
+
+func (t *Tailer) readLines() ([]string, error) {
+	var lines []string
+	for {
+		chunk, err := t.reader.ReadString('\n')
+		t.offset += int64(len(chunk))
+		if err == io.EOF {
+			// Keep an unterminated line until the writer finishes it
+			t.partial += chunk
+			return lines, nil
+		}
+		if err != nil {
+			return lines, err
+		}
+		lines = append(lines, strings.TrimRight(t.partial+chunk, "\r\n"))
+		t.partial = ""
+	}
+}
+
+// Close closes the current file
+func (t *Tailer) Close() error {
+	return t.file.Close()
+}
+
+// Follower emits each bucket of a tailed log once its window has elapsed
+// (plus a lateness allowance for out-of-order lines)
+type Follower struct {
+	Tailer     *Tailer
+	Parser     Parser
+	Times      *TimeParser
+	Aggregator *Aggregator
+	Writer     BucketWriter
+	Lateness   time.Duration
+	Interval   time.Duration
+	Report     func(*ParseError)
+
+	// Detect is set when the parser should be detected from the first lines
+	Detect bool
+
+	line int
+}
+
+// Run follows the file until ctx is cancelled, then reads the lines appended
+// since the last poll, flushes every open bucket and closes the writer
+func (f *Follower) Run(ctx context.Context) error {
+	ticker := time.NewTicker(f.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Pick up the lines appended since the last tick before flushing
+			lines, err := f.Tailer.Poll()
+			if err != nil {
+				return err
+			}
+			if err := f.handleLines(lines, time.Now()); err != nil {
+				return err
+			}
+			for _, bucket := range f.Aggregator.Buckets() {
+				if err := f.Writer.WriteBucket(bucket); err != nil {
+					return err
+				}
+			}
+			return f.Writer.Close()
+		case now := <-ticker.C:
+			lines, err := f.Tailer.Poll()
+			if err != nil {
+				return err
+			}
+			if err := f.handleLines(lines, now); err != nil {
+				return err
+			}
+			for _, bucket := range f.Aggregator.TakeClosed(now, f.Lateness) {
+				if err := f.Writer.WriteBucket(bucket); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (f *Follower) handleLines(lines []string, now time.Time) error {
+	if f.Detect && len(lines) > 0 {
+		profile, err := DetectProfile(lines)
+		if err != nil {
+			return err
+		}
+		if f.Parser, err = profile.NewParser(); err != nil {
+			return err
+		}
+		f.Detect = false
+	}
+
+	for _, line := range lines {
+		f.line++
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		event, err := f.Parser.Parse(line)
+		if errors.Is(err, ErrNotEvent) {
+			continue
+		}
+		if err == nil {
+			event.Time, err = f.Times.Parse(event.Timestamp)
+		}
+		if err == nil && f.Aggregator.IsClosed(event.Time, now, f.Lateness) {
+			err = errors.New("event arrived after its bucket was emitted")
+		}
+		if err == nil {
+			err = f.Aggregator.Add(event)
+		}
+		if err != nil {
+			f.Report(&ParseError{Line: f.line, Text: line, Err: err})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package logparser
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFollowerRunReadsLinesAppendedBeforeShutdown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("[2025-05-26T10:44:00.000+07:00] Book created: old\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tailer, err := NewTailer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tailer.Close()
+
+	// Appended after the last poll; the interval is long enough that no tick runs
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	f.WriteString("[" + now.Format("2006-01-02T15:04:05.000Z07:00") + "] Book created: a\n")
+	f.Close()
+
+	profile, err := ProfileByName("example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser, err := profile.NewParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	writer, err := NewBucketWriter("ndjson", &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	follower := &Follower{
+		Tailer:     tailer,
+		Parser:     parser,
+		Times:      NewTimeParser(time.UTC),
+		Aggregator: newMinuteAggregator(t, nil),
+		Writer:     writer,
+		Lateness:   time.Hour,
+		Interval:   time.Hour,
+		Report:     func(err *ParseError) { t.Errorf("unexpected parse error: %v", err) },
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := follower.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"bucket":"` + now.Format("2006-01-02T15:04Z07:00") + `","created":["a"]}` + "\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,82 @@
+package logparser
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// StdinName is the input name that reads from standard input
+const StdinName = "-"
+
+// ExpandInputs resolves glob patterns to file names, keeping "-" for stdin
+func ExpandInputs(args []string) ([]string, error) {
+	var names []string
+	for _, arg := range args {
+		if arg == StdinName {
+			names = append(names, arg)
+			continue
+		}
+
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", arg, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no input matches %q", arg)
+		}
+		names = append(names, matches...)
+	}
+	return names, nil
+}
+
+// input is an opened log source, transparently decompressed
+type input struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (in *input) Close() error {
+	var firstErr error
+	for i := len(in.closers) - 1; i >= 0; i-- {
+		if err := in.closers[i].Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// OpenInput opens a file (or stdin for "-"), decompressing gzip input
+// detected by its magic bytes
+func OpenInput(name string, stdin io.Reader) (io.ReadCloser, error) {
+	in := &input{}
+
+	var r io.Reader = stdin
+	if name != StdinName {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		in.closers = append(in.closers, f)
+		r = f
+	}
+
+	buffered := bufio.NewReader(r)
+	in.Reader = buffered
+
+	magic, _ := buffered.Peek(2)
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			in.Close()
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		in.closers = append(in.closers, gz)
+		in.Reader = gz
+	}
+
+	return in, nil
+}
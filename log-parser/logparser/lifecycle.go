@@ -0,0 +1,190 @@
+package logparser
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Book states in a lifecycle report
+const (
+	StateAlive   = "alive"
+	StateDeleted = "deleted"
+	// StateUnknown is an ID that was only ever updated
+	StateUnknown = "unknown"
+)
+
+// Anomaly kinds found while replaying
+const (
+	AnomalyDeleteWithoutCreate = "delete_without_create"
+	AnomalyDoubleDelete        = "double_delete"
+	AnomalyDoubleCreate        = "double_create"
+	AnomalyRecreateAfterDelete = "recreate_after_delete"
+	AnomalyUpdateWithoutCreate = "update_without_create"
+	AnomalyUpdateAfterDelete   = "update_after_delete"
+)
+
+// LifecycleFormats lists the -format values supported with -lifecycle
+var LifecycleFormats = []string{"json", "json-compact", "csv"}
+
+// Lifecycle is the replayed history of a single ID
+type Lifecycle struct {
+	ID           string     `json:"id"`
+	State        string     `json:"state"`
+	FirstCreated *time.Time `json:"first_created,omitempty"`
+	LastDeleted  *time.Time `json:"last_deleted,omitempty"`
+	Events       int        `json:"events"`
+	Anomalies    []string   `json:"anomalies,omitempty"`
+}
+
+// Anomaly is an event that does not fit the ID's state at that time
+type Anomaly struct {
+	ID     string    `json:"id"`
+	Kind   string    `json:"kind"`
+	Action string    `json:"action"`
+	Time   time.Time `json:"time"`
+}
+
+// LifecycleReport is the net state of every ID after replaying the log
+type LifecycleReport struct {
+	Alive     []string     `json:"alive"`
+	Books     []*Lifecycle `json:"books"`
+	Anomalies []Anomaly    `json:"anomalies"`
+}
+
+// Replayer collects events and replays them in timestamp order, since log
+// files are not guaranteed to be written in order
+type Replayer struct {
+	bucketer *Bucketer
+	events   []Event
+}
+
+// NewReplayer creates an empty replayer. Times in the report are converted
+// with the bucketer's timezone.
+func NewReplayer(bucketer *Bucketer) *Replayer {
+	return &Replayer{bucketer: bucketer}
+}
+
+// Add records an event whose Time has been parsed
+func (r *Replayer) Add(event Event) error {
+	event.Time = r.bucketer.In(event.Time)
+	r.events = append(r.events, event)
+	return nil
+}
+
+// Merge appends the events of other, which should come later in the input
+func (r *Replayer) Merge(other *Replayer) {
+	r.events = append(r.events, other.events...)
+}
+
+// Report replays every event added so far
+func (r *Replayer) Report() *LifecycleReport {
+	// Stable so events with the same timestamp keep their input order
+	events := make([]Event, len(r.events))
+	copy(events, r.events)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+
+	report := &LifecycleReport{Alive: []string{}, Books: []*Lifecycle{}, Anomalies: []Anomaly{}}
+	books := make(map[string]*Lifecycle)
+
+	for _, event := range events {
+		book, ok := books[event.ID]
+		if !ok {
+			book = &Lifecycle{ID: event.ID, State: StateUnknown}
+			books[event.ID] = book
+			report.Books = append(report.Books, book)
+		}
+		book.Events++
+
+		kind := ""
+		switch event.Action {
+		case "created":
+			switch {
+			case book.State == StateAlive:
+				kind = AnomalyDoubleCreate
+			case book.State == StateDeleted:
+				kind = AnomalyRecreateAfterDelete
+			}
+			if book.FirstCreated == nil {
+				t := event.Time
+				book.FirstCreated = &t
+			}
+			book.State = StateAlive
+		case "deleted":
+			switch {
+			case book.State == StateDeleted:
+				kind = AnomalyDoubleDelete
+			case book.State != StateAlive:
+				kind = AnomalyDeleteWithoutCreate
+			}
+			t := event.Time
+			book.LastDeleted = &t
+			book.State = StateDeleted
+		case "updated":
+			switch book.State {
+			case StateDeleted:
+				kind = AnomalyUpdateAfterDelete
+			case StateUnknown:
+				kind = AnomalyUpdateWithoutCreate
+			}
+		}
+
+		if kind != "" {
+			book.Anomalies = append(book.Anomalies, kind)
+			report.Anomalies = append(report.Anomalies, Anomaly{ID: event.ID, Kind: kind, Action: event.Action, Time: event.Time})
+		}
+	}
+
+	sort.Slice(report.Books, func(i, j int) bool {
+		return report.Books[i].ID < report.Books[j].ID
+	})
+	for _, book := range report.Books {
+		if book.State == StateAlive {
+			report.Alive = append(report.Alive, book.ID)
+		}
+	}
+	return report
+}
+
+// WriteLifecycle writes the report in one of LifecycleFormats
+func WriteLifecycle(w io.Writer, format string, report *LifecycleReport) error {
+	switch format {
+	case "json", "json-compact":
+		encoder := json.NewEncoder(w)
+		if format == "json" {
+			encoder.SetIndent("", "    ")
+		}
+		return encoder.Encode(report)
+	case "csv":
+		// one row per ID; anomalies are joined with ";"
+		out := csv.NewWriter(w)
+		out.Write([]string{"id", "state", "first_created", "last_deleted", "events", "anomalies"})
+		for _, book := range report.Books {
+			out.Write([]string{
+				book.ID,
+				book.State,
+				formatOptionalTime(book.FirstCreated),
+				formatOptionalTime(book.LastDeleted),
+				fmt.Sprint(book.Events),
+				strings.Join(book.Anomalies, ";"),
+			})
+		}
+		out.Flush()
+		return out.Error()
+	default:
+		return fmt.Errorf("format %q is not supported with -lifecycle, expected one of %s", format, strings.Join(LifecycleFormats, ", "))
+	}
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,77 @@
+package logparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReplayerReport(t *testing.T) {
+	bucketer, err := NewBucketer("minute", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		events    []Event
+		state     string
+		anomalies []string
+	}{
+		{
+			name:   "replayed in timestamp order, not input order",
+			events: []Event{event("deleted", "a", 46, 0), event("created", "a", 45, 0)},
+			state:  StateDeleted,
+		},
+		{
+			name:      "delete without create",
+			events:    []Event{event("deleted", "a", 45, 0)},
+			state:     StateDeleted,
+			anomalies: []string{AnomalyDeleteWithoutCreate},
+		},
+		{
+			name:      "double create and double delete",
+			events:    []Event{event("created", "a", 45, 0), event("created", "a", 45, 1), event("deleted", "a", 45, 2), event("deleted", "a", 45, 3)},
+			state:     StateDeleted,
+			anomalies: []string{AnomalyDoubleCreate, AnomalyDoubleDelete},
+		},
+		{
+			name:      "re-create after delete",
+			events:    []Event{event("created", "a", 45, 0), event("deleted", "a", 45, 1), event("created", "a", 45, 2)},
+			state:     StateAlive,
+			anomalies: []string{AnomalyRecreateAfterDelete},
+		},
+		{
+			name:      "update without create",
+			events:    []Event{event("updated", "a", 45, 0)},
+			state:     StateUnknown,
+			anomalies: []string{AnomalyUpdateWithoutCreate},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replayer := NewReplayer(bucketer)
+			for _, e := range tt.events {
+				replayer.Add(e)
+			}
+
+			report := replayer.Report()
+			if len(report.Books) != 1 {
+				t.Fatalf("Report() books = %d, want 1", len(report.Books))
+			}
+			book := report.Books[0]
+			if book.State != tt.state {
+				t.Errorf("state = %s, want %s", book.State, tt.state)
+			}
+			if !reflect.DeepEqual(book.Anomalies, tt.anomalies) {
+				t.Errorf("anomalies = %v, want %v", book.Anomalies, tt.anomalies)
+			}
+			if len(report.Anomalies) != len(tt.anomalies) {
+				t.Errorf("report anomalies = %d, want %d", len(report.Anomalies), len(tt.anomalies))
+			}
+			if alive := len(report.Alive) == 1; alive != (tt.state == StateAlive) {
+				t.Errorf("alive = %v, want %s", report.Alive, tt.state)
+			}
+		})
+	}
+}
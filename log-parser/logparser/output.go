@@ -0,0 +1,211 @@
+package logparser
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OutputFormats lists the values accepted by -format
+var OutputFormats = []string{"json", "json-compact", "ndjson", "csv", "prometheus"}
+
+// Bucket is the aggregated activity of one time bucket
+type Bucket struct {
+	Key   string
+	Start time.Time
+	DataLine
+}
+
+// SortBuckets orders buckets chronologically
+func SortBuckets(buckets []*Bucket) {
+	sort.Slice(buckets, func(i, j int) bool {
+		if !buckets[i].Start.Equal(buckets[j].Start) {
+			return buckets[i].Start.Before(buckets[j].Start)
+		}
+		return buckets[i].Key < buckets[j].Key
+	})
+}
+
+// BucketWriter writes buckets to an output one at a time. Each bucket is
+// flushed once written so followers of the output see it immediately.
+type BucketWriter interface {
+	WriteBucket(bucket *Bucket) error
+	// Close finishes the document and flushes buffered output
+	Close() error
+}
+
+// NewBucketWriter creates a writer for one of OutputFormats
+func NewBucketWriter(format string, w io.Writer) (BucketWriter, error) {
+	buffered := bufio.NewWriter(w)
+
+	switch format {
+	case "json":
+		return &jsonWriter{w: buffered, indent: "    "}, nil
+	case "json-compact":
+		return &jsonWriter{w: buffered}, nil
+	case "ndjson":
+		return &ndjsonWriter{w: buffered}, nil
+	case "csv":
+		return &csvWriter{w: buffered, csv: csv.NewWriter(buffered)}, nil
+	case "prometheus":
+		return &prometheusWriter{w: buffered}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q, expected one of %s", format, strings.Join(OutputFormats, ", "))
+	}
+}
+
+// jsonWriter writes a single object keyed by bucket, as in output_example.json
+type jsonWriter struct {
+	w       *bufio.Writer
+	indent  string
+	written int
+}
+
+func (j *jsonWriter) WriteBucket(bucket *Bucket) error {
+	key, err := json.Marshal(bucket.Key)
+	if err != nil {
+		return err
+	}
+
+	var value []byte
+	if j.indent != "" {
+		value, err = json.MarshalIndent(bucket.DataLine, j.indent, j.indent)
+	} else {
+		value, err = json.Marshal(bucket.DataLine)
+	}
+	if err != nil {
+		return err
+	}
+
+	separator := ","
+	if j.written == 0 {
+		separator = "{"
+	}
+	if j.indent != "" {
+		fmt.Fprintf(j.w, "%s\n%s%s: %s", separator, j.indent, key, value)
+	} else {
+		fmt.Fprintf(j.w, "%s%s:%s", separator, key, value)
+	}
+	j.written++
+	return j.w.Flush()
+}
+
+func (j *jsonWriter) Close() error {
+	switch {
+	case j.written == 0:
+		j.w.WriteString("{}")
+	case j.indent != "":
+		j.w.WriteString("\n}")
+	default:
+		j.w.WriteString("}")
+	}
+	j.w.WriteString("\n")
+	return j.w.Flush()
+}
+
+// ndjsonWriter writes one {"bucket": ..., "<action>": [...], ...} object per line
+type ndjsonWriter struct {
+	w *bufio.Writer
+}
+
+func (n *ndjsonWriter) WriteBucket(bucket *Bucket) error {
+	key, err := json.Marshal(bucket.Key)
+	if err != nil {
+		return err
+	}
+	value, err := json.Marshal(bucket.DataLine)
+	if err != nil {
+		return err
+	}
+
+	// Splice the bucket key in front of the actions so it always comes first
+	fmt.Fprintf(n.w, `{"bucket":%s`, key)
+	if len(bucket.DataLine) > 0 {
+		fmt.Fprintf(n.w, ",%s", value[1:])
+	} else {
+		n.w.WriteString("}")
+	}
+	n.w.WriteString("\n")
+	return n.w.Flush()
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.w.Flush()
+}
+
+// csvWriter writes one bucket,action,id row per event
+type csvWriter struct {
+	w      *bufio.Writer
+	csv    *csv.Writer
+	header bool
+}
+
+func (c *csvWriter) WriteBucket(bucket *Bucket) error {
+	if !c.header {
+		if err := c.csv.Write([]string{"bucket", "action", "id"}); err != nil {
+			return err
+		}
+		c.header = true
+	}
+
+	for _, action := range bucket.Actions() {
+		for _, id := range bucket.DataLine[action] {
+			if err := c.csv.Write([]string{bucket.Key, action, id}); err != nil {
+				return err
+			}
+		}
+	}
+	c.csv.Flush()
+	if err := c.csv.Error(); err != nil {
+		return err
+	}
+	return c.w.Flush()
+}
+
+func (c *csvWriter) Close() error {
+	if !c.header {
+		c.csv.Write([]string{"bucket", "action", "id"})
+	}
+	c.csv.Flush()
+	if err := c.csv.Error(); err != nil {
+		return err
+	}
+	return c.w.Flush()
+}
+
+// prometheusWriter writes counts only, in the Prometheus text exposition
+// format with the bucket start as sample timestamp, ready for charting
+type prometheusWriter struct {
+	w      *bufio.Writer
+	header bool
+}
+
+func (p *prometheusWriter) WriteBucket(bucket *Bucket) error {
+	if !p.header {
+		p.writeHeader()
+	}
+
+	timestamp := bucket.Start.UnixMilli()
+	for _, action := range bucket.Actions() {
+		fmt.Fprintf(p.w, "logparser_events{bucket=%q,action=%q} %d %d\n", bucket.Key, action, len(bucket.DataLine[action]), timestamp)
+	}
+	return p.w.Flush()
+}
+
+func (p *prometheusWriter) writeHeader() {
+	p.w.WriteString("# HELP logparser_events Number of book events per time bucket and action.\n")
+	p.w.WriteString("# TYPE logparser_events gauge\n")
+	p.header = true
+}
+
+func (p *prometheusWriter) Close() error {
+	if !p.header {
+		p.writeHeader()
+	}
+	return p.w.Flush()
+}
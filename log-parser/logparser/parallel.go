@@ -0,0 +1,123 @@
+package logparser
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sync"
+)
+
+// MinParallelSize is the smallest input worth splitting across workers
+const MinParallelSize = 1 << 20
+
+// MaxChunkErrors is the number of parse errors ScanParallel keeps per chunk
+// until every chunk is done; further errors are only counted
+const MaxChunkErrors = 1000
+
+// SplitChunks divides the first size bytes of r into at most n ranges of
+// roughly equal size, each ending just after a newline (or at size) so no
+// line is split between ranges. The result holds the n+1 range boundaries.
+func SplitChunks(r io.ReaderAt, size int64, n int) ([]int64, error) {
+	if n < 1 {
+		n = 1
+	}
+
+	bounds := []int64{0}
+	for i := 1; i < n; i++ {
+		prev := bounds[len(bounds)-1]
+		target := size * int64(i) / int64(n)
+		if target <= prev {
+			continue
+		}
+
+		end, err := nextLineStart(r, target, size)
+		if err != nil {
+			return nil, err
+		}
+		if end > prev && end < size {
+			bounds = append(bounds, end)
+		}
+	}
+	return append(bounds, size), nil
+}
+
+// nextLineStart returns the offset just past the first newline at or after
+// offset, or size when there is none
+func nextLineStart(r io.ReaderAt, offset, size int64) (int64, error) {
+	buf := make([]byte, 64*1024)
+	for offset < size {
+		n, err := r.ReadAt(buf[:min(int64(len(buf)), size-offset)], offset)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			return offset + int64(i) + 1, nil
+		}
+		offset += int64(n)
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if n == 0 {
+			break
+		}
+	}
+	return size, nil
+}
+
+// ScanParallel splits the first size bytes of r into up to workers
+// newline-aligned chunks and scans each in its own goroutine, streaming from
+// r so memory stays bounded by the scanner buffers. handle receives the chunk
+// index with every event, so callers can keep one partial result per chunk
+// and merge them in order afterwards; it is called concurrently for different
+// chunks, and Event.Line is relative to the chunk. Parse errors are passed to
+// report in input order, with line numbers of the whole input, once every
+// chunk is done; only the first MaxChunkErrors of each chunk are kept, so a
+// mostly malformed input does not fill memory. The number of chunks and the
+// number of parse errors not reported are returned.
+func ScanParallel(r io.ReaderAt, size int64, workers int, parser Parser, handle func(chunk int, event Event) error, report func(*ParseError)) (chunks, dropped int, err error) {
+	bounds, err := SplitChunks(r, size, workers)
+	if err != nil {
+		return 0, 0, err
+	}
+	chunks = len(bounds) - 1
+
+	type result struct {
+		lines   int
+		errors  []*ParseError
+		dropped int
+		err     error
+	}
+	results := make([]result, chunks)
+
+	var wg sync.WaitGroup
+	for i := 0; i < chunks; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			res := &results[i]
+			section := bufio.NewReaderSize(io.NewSectionReader(r, bounds[i], bounds[i+1]-bounds[i]), 256*1024)
+			res.lines, res.err = scan(section, parser,
+				func(event Event) error { return handle(i, event) },
+				func(err *ParseError) {
+					if len(res.errors) < MaxChunkErrors {
+						res.errors = append(res.errors, err)
+					} else {
+						res.dropped++
+					}
+				})
+		}(i)
+	}
+	wg.Wait()
+
+	offset := 0
+	for _, res := range results {
+		if res.err != nil {
+			return chunks, dropped, res.err
+		}
+		for _, parseErr := range res.errors {
+			parseErr.Line += offset
+			report(parseErr)
+		}
+		dropped += res.dropped
+		offset += res.lines
+	}
+	return chunks, dropped, nil
+}
@@ -0,0 +1,161 @@
+package logparser
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// benchmarkLog builds an example-profile log of the given number of lines
+func benchmarkLog(lines int) []byte {
+	var buf bytes.Buffer
+	actions := []string{"created", "updated", "deleted"}
+	start := time.Date(2025, 5, 26, 10, 0, 0, 0, time.FixedZone("", 7*60*60))
+	for i := 0; i < lines; i++ {
+		t := start.Add(time.Duration(i) * 100 * time.Millisecond)
+		fmt.Fprintf(&buf, "[%s] Book %s: %08x-af68-46df-bc25-d002490bc84a\n", t.Format("2006-01-02T15:04:05.000-07:00"), actions[i%len(actions)], i)
+	}
+	return buf.Bytes()
+}
+
+func TestScanParallelMatchesScan(t *testing.T) {
+	data := append(benchmarkLog(5000), "garbage\n[2025-05-26T10:45:10.000+07:00] Book created: last"...)
+	parser, err := NewPatternParser("[{timestamp}] Book {action}: {id}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want []Event
+	var wantErrs []int
+	err = Scan(bytes.NewReader(data), parser,
+		func(event Event) error { want = append(want, event); return nil },
+		func(err *ParseError) { wantErrs = append(wantErrs, err.Line) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, workers := range []int{1, 3, 7} {
+		partials := make([][]Event, workers)
+		var gotErrs []int
+		chunks, dropped, err := ScanParallel(bytes.NewReader(data), int64(len(data)), workers, parser,
+			func(chunk int, event Event) error { partials[chunk] = append(partials[chunk], event); return nil },
+			func(err *ParseError) { gotErrs = append(gotErrs, err.Line) })
+		if err != nil || dropped != 0 {
+			t.Fatalf("ScanParallel(%d) = %d dropped, error %v", workers, dropped, err)
+		}
+		if chunks != workers {
+			t.Errorf("ScanParallel(%d) used %d chunks", workers, chunks)
+		}
+
+		var got []Event
+		for _, partial := range partials {
+			for _, event := range partial {
+				// Line is relative to the chunk, compare the events only
+				event.Line = 0
+				got = append(got, event)
+			}
+		}
+		if len(got) != len(want) {
+			t.Fatalf("ScanParallel(%d) = %d events, want %d", workers, len(got), len(want))
+		}
+		for i := range want {
+			want[i].Line = 0
+			if got[i] != want[i] {
+				t.Fatalf("ScanParallel(%d) event %d = %+v, want %+v", workers, i, got[i], want[i])
+			}
+		}
+		if !reflect.DeepEqual(gotErrs, wantErrs) {
+			t.Errorf("ScanParallel(%d) error lines = %v, want %v", workers, gotErrs, wantErrs)
+		}
+	}
+}
+
+func TestScanParallelCapsErrors(t *testing.T) {
+	var buf bytes.Buffer
+	for i := 0; i < 3*MaxChunkErrors; i++ {
+		fmt.Fprintf(&buf, "garbage %d\n", i)
+	}
+	data := append(buf.Bytes(), benchmarkLog(10)...)
+	parser, err := NewPatternParser("[{timestamp}] Book {action}: {id}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, workers := range []int{1, 2, 4} {
+		var lines []int
+		chunks, dropped, err := ScanParallel(bytes.NewReader(data), int64(len(data)), workers, parser,
+			func(chunk int, event Event) error { return nil },
+			func(err *ParseError) { lines = append(lines, err.Line) })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(lines) > chunks*MaxChunkErrors {
+			t.Errorf("ScanParallel(%d) reported %d errors from %d chunks", workers, len(lines), chunks)
+		}
+		if len(lines)+dropped != 3*MaxChunkErrors {
+			t.Errorf("ScanParallel(%d) reported %d and dropped %d errors, want %d in all", workers, len(lines), dropped, 3*MaxChunkErrors)
+		}
+		for i := 1; i < len(lines); i++ {
+			if lines[i] <= lines[i-1] {
+				t.Fatalf("ScanParallel(%d) reported line %d after %d", workers, lines[i], lines[i-1])
+			}
+		}
+		if workers == 1 && (len(lines) != MaxChunkErrors || lines[len(lines)-1] != MaxChunkErrors) {
+			t.Errorf("ScanParallel(1) reported %d errors up to line %d, want the first %d", len(lines), lines[len(lines)-1], MaxChunkErrors)
+		}
+	}
+}
+
+// BenchmarkScanParallel reports throughput (MB/s) for an increasing number of
+// workers; it should scale with the cores available
+func BenchmarkScanParallel(b *testing.B) {
+	data := benchmarkLog(200000)
+	profile, err := ProfileByName("example")
+	if err != nil {
+		b.Fatal(err)
+	}
+	parser, err := profile.NewParser()
+	if err != nil {
+		b.Fatal(err)
+	}
+	bucketer, err := NewBucketer("minute", nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	counts := []int{1, 2, 4, 8}
+	if cpus := runtime.NumCPU(); cpus > 8 {
+		counts = append(counts, cpus)
+	}
+
+	for _, workers := range counts {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				times := NewTimeParser(time.UTC)
+				partials := make([]*Aggregator, workers)
+				for j := range partials {
+					partials[j] = NewAggregator(bucketer, nil)
+				}
+
+				_, _, err := ScanParallel(bytes.NewReader(data), int64(len(data)), workers, parser, func(chunk int, event Event) error {
+					var err error
+					if event.Time, err = times.Parse(event.Timestamp); err != nil {
+						return err
+					}
+					return partials[chunk].Add(event)
+				}, func(err *ParseError) { b.Fatal(err) })
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				for _, partial := range partials[1:] {
+					partials[0].Merge(partial)
+				}
+			}
+		})
+	}
+}
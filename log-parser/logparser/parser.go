@@ -0,0 +1,190 @@
+// Package logparser parses book event log lines ("Book created: <id>") in the
+// formats written by book-management-api and aggregates them into time
+// buckets or a per-ID lifecycle report.
+package logparser
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrNotEvent is returned for well-formed lines that do not describe a book
+// event, such as "Server starting on port :8080"
+var ErrNotEvent = errors.New("line is not a book event")
+
+// Event is a single parsed log line. Time is filled in once Timestamp has
+// been parsed.
+type Event struct {
+	Line      int
+	Timestamp string
+	Time      time.Time
+	Action    string
+	ID        string
+}
+
+// ParseError describes a line that could not be parsed
+type ParseError struct {
+	Line int
+	Text string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v: %q", e.Line, e.Err, e.Text)
+}
+
+// Parser extracts an event from a single line
+type Parser interface {
+	Parse(line string) (Event, error)
+}
+
+// PatternParser extracts events using a regular expression with a timestamp
+// group and either action and id groups or a message group
+type PatternParser struct {
+	re *regexp.Regexp
+}
+
+var placeholder = regexp.MustCompile(`\{(\w+)\}`)
+
+// eventMessage matches the message the book usecase logs for each mutation
+var eventMessage = regexp.MustCompile(`^Book (\w+): (\S+)`)
+
+// NewPatternParser builds a parser from either a regular expression containing
+// named groups, or a named-field pattern such as "[{timestamp}] Book {action}: {id}".
+// The pattern needs a timestamp field plus action and id fields, or a message
+// field holding "Book <action>: <id>".
+func NewPatternParser(pattern string) (*PatternParser, error) {
+	expr := pattern
+	if !strings.Contains(pattern, "(?P<") {
+		expr = compileFieldPattern(pattern)
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	if re.SubexpIndex("timestamp") < 0 {
+		return nil, fmt.Errorf("pattern %q has no timestamp field", pattern)
+	}
+	if re.SubexpIndex("message") < 0 {
+		for _, field := range []string{"action", "id"} {
+			if re.SubexpIndex(field) < 0 {
+				return nil, fmt.Errorf("pattern %q has no %s field", pattern, field)
+			}
+		}
+	}
+
+	return &PatternParser{re: re}, nil
+}
+
+// compileFieldPattern turns "{name}" placeholders into named groups, escapes
+// the literal text in between and lets any run of whitespace match
+func compileFieldPattern(pattern string) string {
+	var expr strings.Builder
+	expr.WriteString("^")
+
+	last := 0
+	for _, loc := range placeholder.FindAllStringSubmatchIndex(pattern, -1) {
+		expr.WriteString(quoteLiteral(pattern[last:loc[0]]))
+		fmt.Fprintf(&expr, "(?P<%s>.+?)", pattern[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	expr.WriteString(quoteLiteral(pattern[last:]))
+
+	expr.WriteString("$")
+	return expr.String()
+}
+
+func quoteLiteral(literal string) string {
+	parts := strings.Fields(literal)
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	quoted := strings.Join(parts, `\s+`)
+	if strings.TrimLeft(literal, " \t") != literal {
+		quoted = `\s+` + quoted
+	}
+	if strings.TrimRight(literal, " \t") != literal && quoted != `\s+` {
+		quoted += `\s+`
+	}
+	return quoted
+}
+
+// Parse extracts an event from a single line
+func (p *PatternParser) Parse(line string) (Event, error) {
+	match := p.re.FindStringSubmatch(line)
+	if match == nil {
+		return Event{}, fmt.Errorf("line does not match pattern")
+	}
+
+	timestamp := match[p.re.SubexpIndex("timestamp")]
+	if p.re.SubexpIndex("action") >= 0 && p.re.SubexpIndex("id") >= 0 {
+		return Event{
+			Timestamp: timestamp,
+			Action:    strings.ToLower(match[p.re.SubexpIndex("action")]),
+			ID:        match[p.re.SubexpIndex("id")],
+		}, nil
+	}
+
+	return eventFromMessage(timestamp, match[p.re.SubexpIndex("message")])
+}
+
+// eventFromMessage extracts the action and id from a "Book <action>: <id>" message
+func eventFromMessage(timestamp, message string) (Event, error) {
+	match := eventMessage.FindStringSubmatch(strings.TrimSpace(message))
+	if match == nil {
+		return Event{}, ErrNotEvent
+	}
+
+	return Event{
+		Timestamp: timestamp,
+		Action:    strings.ToLower(match[1]),
+		ID:        match[2],
+	}, nil
+}
+
+// Scan reads r line by line, calling handle for every parsed event. Lines
+// that fail to parse are passed to report and scanning continues; lines that
+// are not book events are skipped silently.
+func Scan(r io.Reader, parser Parser, handle func(Event) error, report func(*ParseError)) error {
+	_, err := scan(r, parser, handle, report)
+	return err
+}
+
+// scan is Scan returning the number of lines read
+func scan(r io.Reader, parser Parser, handle func(Event) error, report func(*ParseError)) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+
+		// Tolerate CRLF line endings and blank lines
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		event, err := parser.Parse(line)
+		if errors.Is(err, ErrNotEvent) {
+			continue
+		}
+		if err == nil {
+			event.Line = lineNumber
+			err = handle(event)
+		}
+		if err != nil {
+			report(&ParseError{Line: lineNumber, Text: line, Err: err})
+		}
+	}
+
+	return lineNumber, scanner.Err()
+}
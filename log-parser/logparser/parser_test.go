@@ -0,0 +1,238 @@
+package logparser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestPatternParserParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		line    string
+		want    Event
+		wantErr error
+		fails   bool
+	}{
+		{
+			name:    "named fields",
+			pattern: "[{timestamp}] Book {action}: {id}",
+			line:    "[2025-05-26T10:45:10.000+07:00] Book created: 3f02135f",
+			want:    Event{Timestamp: "2025-05-26T10:45:10.000+07:00", Action: "created", ID: "3f02135f"},
+		},
+		{
+			name:    "named fields tolerate extra whitespace",
+			pattern: "[{timestamp}] Book {action}: {id}",
+			line:    "[2025-05-26T10:45:10.000+07:00]   Book  Deleted:  9e3cedf8",
+			want:    Event{Timestamp: "2025-05-26T10:45:10.000+07:00", Action: "deleted", ID: "9e3cedf8"},
+		},
+		{
+			name:    "regex with action and id",
+			pattern: `^(?P<timestamp>\S+) (?P<action>\w+) (?P<id>\S+)$`,
+			line:    "2025-05-26T10:45:10Z updated 978-0134190440",
+			want:    Event{Timestamp: "2025-05-26T10:45:10Z", Action: "updated", ID: "978-0134190440"},
+		},
+		{
+			name:    "regex with message",
+			pattern: `^(?P<timestamp>\S+) (?P<message>.*)$`,
+			line:    "2025-05-26T10:45:10Z Book archived: 978-0134190440",
+			want:    Event{Timestamp: "2025-05-26T10:45:10Z", Action: "archived", ID: "978-0134190440"},
+		},
+		{
+			name:    "message that is not a book event",
+			pattern: `^(?P<timestamp>\S+) (?P<message>.*)$`,
+			line:    "2025-05-26T10:45:10Z Server starting on port :8080",
+			wantErr: ErrNotEvent,
+		},
+		{
+			name:    "line that does not match",
+			pattern: "[{timestamp}] Book {action}: {id}",
+			line:    "garbage",
+			fails:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := NewPatternParser(tt.pattern)
+			if err != nil {
+				t.Fatalf("NewPatternParser() error = %v", err)
+			}
+
+			got, err := parser.Parse(tt.line)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.fails:
+				if err == nil {
+					t.Fatalf("Parse() = %+v, want an error", got)
+				}
+			case err != nil:
+				t.Fatalf("Parse() error = %v", err)
+			case got != tt.want:
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPatternParserRejectsIncompletePatterns(t *testing.T) {
+	patterns := []string{
+		"Book {action}: {id}",
+		"[{timestamp}] Book {action}",
+		`^(?P<timestamp>\S+) (?P<id>\S+)$`,
+		`^(?P<timestamp>[)$`,
+	}
+
+	for _, pattern := range patterns {
+		if _, err := NewPatternParser(pattern); err == nil {
+			t.Errorf("NewPatternParser(%q) succeeded, want an error", pattern)
+		}
+	}
+}
+
+func TestJSONParserParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		want  Event
+		fails bool
+	}{
+		{
+			name: "message field",
+			line: `{"time":"2025-05-26T10:45:10Z","level":"INFO","message":"Book created: 978-0134190440"}`,
+			want: Event{Timestamp: "2025-05-26T10:45:10Z", Action: "created", ID: "978-0134190440"},
+		},
+		{
+			name: "action and isbn fields",
+			line: `{"timestamp":"2025-05-26T10:45:10Z","action":"Deleted","isbn":"978-0134190440"}`,
+			want: Event{Timestamp: "2025-05-26T10:45:10Z", Action: "deleted", ID: "978-0134190440"},
+		},
+		{name: "missing time", line: `{"message":"Book created: 1"}`, fails: true},
+		{name: "missing id", line: `{"time":"2025-05-26T10:45:10Z","action":"created"}`, fails: true},
+		{name: "not JSON", line: `Book created: 1`, fails: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONParser{}.Parse(tt.line)
+			if tt.fails {
+				if err == nil {
+					t.Fatalf("Parse() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectProfile(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{
+			name: "asynclogger",
+			lines: []string{
+				"2025/05/26 10:45:10 [LOG] INFO: Server starting on port :8080",
+				"2025/05/26 10:45:11 [LOG] INFO: Book created: 978-0134190440",
+			},
+			want: "asynclogger",
+		},
+		{
+			name:  "example",
+			lines: []string{"[2025-05-26T10:45:10.000+07:00] Book created: 3f02135f"},
+			want:  "example",
+		},
+		{
+			name:  "jsonl",
+			lines: []string{`{"time":"2025-05-26T10:45:10Z","message":"Book created: 1"}`},
+			want:  "jsonl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectProfile(tt.lines)
+			if err != nil {
+				t.Fatalf("DetectProfile() error = %v", err)
+			}
+			if got.Name != tt.want {
+				t.Errorf("DetectProfile() = %s, want %s", got.Name, tt.want)
+			}
+		})
+	}
+
+	if _, err := DetectProfile([]string{"garbage"}); err == nil {
+		t.Error("DetectProfile() of unrecognised lines succeeded, want an error")
+	}
+}
+
+func TestScanReportsMalformedLines(t *testing.T) {
+	input := "[2025-05-26T10:45:10.000+07:00] Book created: a\r\n\ngarbage\n[2025-05-26T10:45:11.000+07:00] Book deleted: b\n"
+	parser, err := NewPatternParser("[{timestamp}] Book {action}: {id}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events []Event
+	var errs []*ParseError
+	err = Scan(strings.NewReader(input), parser,
+		func(event Event) error { events = append(events, event); return nil },
+		func(err *ParseError) { errs = append(errs, err) })
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	if len(events) != 2 || events[0].ID != "a" || events[1].ID != "b" || events[1].Line != 4 {
+		t.Errorf("Scan() events = %+v", events)
+	}
+	if len(errs) != 1 || errs[0].Line != 3 {
+		t.Errorf("Scan() errors = %+v, want line 3 only", errs)
+	}
+}
+
+// FuzzParse checks that no profile panics on arbitrary input and that every
+// event returned has a timestamp, a lower-case action and an id
+func FuzzParse(f *testing.F) {
+	f.Add("[2025-05-26T10:45:10.000+07:00] Book created: 3f02135f")
+	f.Add("2025/05/26 10:45:10 [LOG] INFO: Book deleted: 978-0134190440")
+	f.Add(`{"time":"2025-05-26T10:45:10Z","action":"created","id":"1"}`)
+	f.Add("[] Book :")
+	f.Add("")
+
+	var parsers []Parser
+	for _, profile := range Profiles {
+		parser, err := profile.NewParser()
+		if err != nil {
+			f.Fatal(err)
+		}
+		parsers = append(parsers, parser)
+	}
+
+	f.Fuzz(func(t *testing.T, line string) {
+		for _, parser := range parsers {
+			event, err := parser.Parse(line)
+			if err != nil {
+				continue
+			}
+			if event.Timestamp == "" || event.Action == "" || event.ID == "" {
+				t.Errorf("Parse(%q) = %+v, want every field set", line, event)
+			}
+			if utf8.ValidString(event.Action) && event.Action != strings.ToLower(event.Action) {
+				t.Errorf("Parse(%q) action %q is not lower case", line, event.Action)
+			}
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package logparser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Profile is a built-in log format
+type Profile struct {
+	Name        string
+	Description string
+	pattern     string
+	newParser   func() (Parser, error)
+}
+
+// Profiles lists the built-in formats in detection order
+var Profiles = []Profile{
+	{
+		Name:        "asynclogger",
+		Description: "book-management-api AsyncLogger: 2006/01/02 15:04:05 [LOG] INFO: Book created: <isbn>",
+		pattern:     `^(?P<timestamp>\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}) \[LOG\] (?P<level>[A-Z]+): (?P<message>.*)$`,
+	},
+	{
+		Name:        "example",
+		Description: "app.log.example: [2025-05-26T10:45:10.000+07:00] Book created: <uuid>",
+		pattern:     `^\[(?P<timestamp>[^\]]+)\] (?P<message>.*)$`,
+	},
+	{
+		Name:        "jsonl",
+		Description: `JSON lines: {"time": "...", "message": "Book created: <id>"} or {"time": "...", "action": "created", "id": "<id>"}`,
+		newParser:   func() (Parser, error) { return JSONParser{}, nil },
+	},
+}
+
+// NewParser creates a parser for the profile
+func (p Profile) NewParser() (Parser, error) {
+	if p.newParser != nil {
+		return p.newParser()
+	}
+	return NewPatternParser(p.pattern)
+}
+
+// ProfileByName looks up a built-in profile
+func ProfileByName(name string) (Profile, error) {
+	var names []string
+	for _, profile := range Profiles {
+		if profile.Name == name {
+			return profile, nil
+		}
+		names = append(names, profile.Name)
+	}
+	return Profile{}, fmt.Errorf("unknown profile %q, expected one of auto, %s", name, strings.Join(names, ", "))
+}
+
+// DetectProfile picks the profile recognising the most of the given lines
+func DetectProfile(lines []string) (Profile, error) {
+	best, bestCount := -1, 0
+	for i, profile := range Profiles {
+		parser, err := profile.NewParser()
+		if err != nil {
+			return Profile{}, err
+		}
+
+		count := 0
+		for _, line := range lines {
+			if _, err := parser.Parse(line); err == nil || errors.Is(err, ErrNotEvent) {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = i, count
+		}
+	}
+
+	if best < 0 {
+		return Profile{}, errors.New("unable to detect log format from input")
+	}
+	return Profiles[best], nil
+}
+
+// detectSampleLines is how many non-empty lines auto-detection looks at
+const detectSampleLines = 20
+
+// DetectReader detects the profile from the first lines of r and returns a
+// reader that still yields the whole input
+func DetectReader(r io.Reader) (Profile, io.Reader, error) {
+	reader := bufio.NewReader(r)
+	var consumed bytes.Buffer
+	var lines []string
+
+	for len(lines) < detectSampleLines {
+		line, err := reader.ReadString('\n')
+		consumed.WriteString(line)
+		if trimmed := strings.TrimRight(line, "\r\n"); strings.TrimSpace(trimmed) != "" {
+			lines = append(lines, trimmed)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Profile{}, nil, err
+		}
+	}
+
+	profile, err := DetectProfile(lines)
+	if err != nil {
+		return Profile{}, nil, err
+	}
+	return profile, io.MultiReader(&consumed, reader), nil
+}
+
+// SelectParser resolves the parser from a custom pattern, a named profile, or
+// by detecting the profile from the first lines of r
+func SelectParser(r io.Reader, profileName, pattern string) (io.Reader, Parser, error) {
+	if pattern != "" {
+		parser, err := NewPatternParser(pattern)
+		return r, parser, err
+	}
+
+	var profile Profile
+	var err error
+	if profileName == "auto" {
+		profile, r, err = DetectReader(r)
+	} else {
+		profile, err = ProfileByName(profileName)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parser, err := profile.NewParser()
+	return r, parser, err
+}
+
+// JSONParser reads one JSON object per line, as written by the AsyncLogger's
+// JSON formatter
+type JSONParser struct{}
+
+type jsonLine struct {
+	Time      string `json:"time"`
+	Timestamp string `json:"timestamp"`
+	Message   string `json:"message"`
+	Msg       string `json:"msg"`
+	Action    string `json:"action"`
+	ID        string `json:"id"`
+	ISBN      string `json:"isbn"`
+}
+
+func (JSONParser) Parse(line string) (Event, error) {
+	var record jsonLine
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return Event{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	timestamp := firstNonEmpty(record.Time, record.Timestamp)
+	if timestamp == "" {
+		return Event{}, errors.New("missing time field")
+	}
+
+	if record.Action != "" {
+		id := firstNonEmpty(record.ID, record.ISBN)
+		if id == "" {
+			return Event{}, errors.New("missing id field")
+		}
+		return Event{Timestamp: timestamp, Action: strings.ToLower(record.Action), ID: id}, nil
+	}
+
+	return eventFromMessage(timestamp, firstNonEmpty(record.Message, record.Msg))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
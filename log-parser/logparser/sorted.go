@@ -0,0 +1,52 @@
+package logparser
+
+import (
+	"errors"
+	"time"
+)
+
+// SortedEmitter writes buckets of input in timestamp order as soon as an
+// event of a later bucket arrives, so only the open bucket is held in memory.
+// Lines within a bucket may be out of order; a line whose bucket was already
+// written is rejected.
+type SortedEmitter struct {
+	Aggregator *Aggregator
+	Writer     BucketWriter
+
+	latest time.Time
+	// err is the first write error, reported by Close rather than as a bad line
+	err error
+}
+
+// Add records an event whose Time has been parsed, first writing the buckets
+// that ended before it
+func (s *SortedEmitter) Add(event Event) error {
+	if s.err != nil {
+		return nil
+	}
+	if !s.latest.IsZero() && s.Aggregator.IsClosed(event.Time, s.latest, 0) {
+		return errors.New("event is out of timestamp order and its bucket was already written")
+	}
+	if event.Time.After(s.latest) {
+		s.latest = event.Time
+		for _, bucket := range s.Aggregator.TakeClosed(event.Time, 0) {
+			if s.err = s.Writer.WriteBucket(bucket); s.err != nil {
+				return nil
+			}
+		}
+	}
+	return s.Aggregator.Add(event)
+}
+
+// Close writes the buckets still open and closes the writer
+func (s *SortedEmitter) Close() error {
+	if s.err != nil {
+		return s.err
+	}
+	for _, bucket := range s.Aggregator.Buckets() {
+		if err := s.Writer.WriteBucket(bucket); err != nil {
+			return err
+		}
+	}
+	return s.Writer.Close()
+}
@@ -0,0 +1,51 @@
+package logparser
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSortedEmitterWritesClosedBuckets(t *testing.T) {
+	var out bytes.Buffer
+	writer, err := NewBucketWriter("ndjson", &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	emitter := &SortedEmitter{Aggregator: newMinuteAggregator(t, nil), Writer: writer}
+
+	// Lines may be out of order within a bucket
+	for _, e := range []Event{
+		event("created", "a", 45, 30),
+		event("created", "b", 45, 10),
+	} {
+		if err := emitter.Add(e); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	if out.Len() != 0 {
+		t.Fatalf("bucket written before it closed: %s", out.String())
+	}
+
+	if err := emitter.Add(event("deleted", "a", 46, 0)); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if want := `{"bucket":"2025-05-26T10:45+07:00","created":["a","b"]}` + "\n"; out.String() != want {
+		t.Fatalf("after the next bucket started, output = %q, want %q", out.String(), want)
+	}
+	if remaining := emitter.Aggregator.Buckets(); len(remaining) != 1 {
+		t.Errorf("aggregator holds %d buckets, want only the open one", len(remaining))
+	}
+
+	if err := emitter.Add(event("created", "c", 45, 50)); err == nil {
+		t.Error("Add() accepted an event of a bucket already written")
+	}
+
+	if err := emitter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"bucket":"2025-05-26T10:45+07:00","created":["a","b"]}` + "\n" +
+		`{"bucket":"2025-05-26T10:46+07:00","created":[],"deleted":["a"]}` + "\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,126 @@
+package logparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// TimeParser parses log timestamps, trying a list of layouts modelled on
+// book-management-api's internal/parser. Timestamps without an offset are
+// read in the assumed location. It is safe for concurrent use without
+// locking, so parallel workers can share one.
+type TimeParser struct {
+	layouts []string
+	assume  *time.Location
+	// last is one plus the index of the layout that matched last, or 0
+	last atomic.Int32
+}
+
+// NewTimeParser creates a parser reading zone-less timestamps in assume
+func NewTimeParser(assume *time.Location) *TimeParser {
+	return &TimeParser{
+		layouts: []string{
+			// RFC3339 and ISO 8601 variants
+			time.RFC3339Nano,
+			"2006-01-02T15:04:05.000Z07:00",
+			"2006-01-02T15:04:05Z0700",
+			"2006-01-02T15:04:05.000Z0700",
+			"2006-01-02T15:04:05",
+			"2006-01-02T15:04:05.000",
+			"2006-01-02T15:04",
+
+			// AsyncLogger and other space separated layouts
+			"2006/01/02 15:04:05",
+			"2006/01/02 15:04:05.000",
+			"2006-01-02 15:04:05",
+			"2006-01-02 15:04:05.000",
+			"2006-01-02 15:04:05Z07:00",
+			"2006-01-02 15:04:05.000Z07:00",
+
+			// Other common log layouts
+			time.RFC1123Z,
+			time.RFC1123,
+			"02/Jan/2006:15:04:05 -0700",
+		},
+		assume: assume,
+	}
+}
+
+// Parse converts a timestamp string to a time, trying the layout that matched
+// last time first since log files rarely mix layouts
+func (p *TimeParser) Parse(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("empty timestamp")
+	}
+
+	if t, ok := parseEpoch(value); ok {
+		return t, nil
+	}
+
+	last := int(p.last.Load()) - 1
+	if last >= 0 {
+		if t, err := time.ParseInLocation(p.layouts[last], value, p.assume); err == nil {
+			return t, nil
+		}
+	}
+
+	for i, layout := range p.layouts {
+		if i == last {
+			continue
+		}
+		if t, err := time.ParseInLocation(layout, value, p.assume); err == nil {
+			p.last.Store(int32(i + 1))
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse timestamp %q", value)
+}
+
+// parseEpoch accepts Unix timestamps in seconds or milliseconds
+func parseEpoch(value string) (time.Time, bool) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	switch len(value) {
+	case 10:
+		return time.Unix(n, 0).UTC(), true
+	case 13:
+		return time.UnixMilli(n).UTC(), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// LoadLocation resolves "Local", "UTC", IANA names and fixed offsets such as "+07:00"
+func LoadLocation(name string) (*time.Location, error) {
+	switch strings.ToLower(name) {
+	case "local":
+		return time.Local, nil
+	case "utc", "z":
+		return time.UTC, nil
+	}
+
+	if name != "" && (name[0] == '+' || name[0] == '-') {
+		t, err := time.Parse("-07:00", name)
+		if err != nil {
+			t, err = time.Parse("-0700", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset %q", name)
+		}
+		_, offset := t.Zone()
+		return time.FixedZone(name, offset), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q: %w", name, err)
+	}
+	return loc, nil
+}
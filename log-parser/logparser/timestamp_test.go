@@ -0,0 +1,64 @@
+package logparser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeParserParse(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{value: "2025-05-26T10:45:10.000+07:00", want: time.Date(2025, 5, 26, 10, 45, 10, 0, jakarta)},
+		{value: "2025-05-26T03:45:10Z", want: time.Date(2025, 5, 26, 3, 45, 10, 0, time.UTC)},
+		{value: "2025/05/26 10:45:10", want: time.Date(2025, 5, 26, 10, 45, 10, 0, jakarta)},
+		{value: "2025-05-26 10:45:10.250", want: time.Date(2025, 5, 26, 10, 45, 10, 250_000_000, jakarta)},
+		{value: "26/May/2025:10:45:10 +0700", want: time.Date(2025, 5, 26, 10, 45, 10, 0, jakarta)},
+		{value: "1748231110", want: time.Date(2025, 5, 26, 3, 45, 10, 0, time.UTC)},
+		{value: "1748231110250", want: time.Date(2025, 5, 26, 3, 45, 10, 250_000_000, time.UTC)},
+	}
+
+	parser := NewTimeParser(jakarta)
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parser.Parse(tt.value)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, value := range []string{"", "yesterday", "2025-13-01T00:00:00Z"} {
+		if got, err := parser.Parse(value); err == nil {
+			t.Errorf("Parse(%q) = %v, want an error", value, got)
+		}
+	}
+}
+
+func TestLoadLocation(t *testing.T) {
+	for _, name := range []string{"Local", "UTC", "Asia/Jakarta", "+07:00", "-0330"} {
+		if _, err := LoadLocation(name); err != nil {
+			t.Errorf("LoadLocation(%q) error = %v", name, err)
+		}
+	}
+	if _, err := LoadLocation("Mars/Olympus"); err == nil {
+		t.Error("LoadLocation() of an unknown zone succeeded, want an error")
+	}
+}
+
+// BenchmarkTimeParserParallel parses from every core with one shared parser,
+// as the workers of ScanParallel do; it should scale with the cores available
+func BenchmarkTimeParserParallel(b *testing.B) {
+	times := NewTimeParser(time.UTC)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := times.Parse("2025-05-26T10:45:10.000+07:00"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
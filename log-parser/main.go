@@ -1,100 +1,432 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"log-parser/logparser"
+)
+
+// Exit codes
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
 )
 
-// struct for structure of
-type DataLine struct {
-	Created []string `json:"created"`
-	Deleted []string `json:"deleted"`
+func printContent(w io.Writer, buckets []*logparser.Bucket, actions []string) {
+	// print counts per bucket in chronological order
+	totals := make(map[string]int)
+	for _, bucket := range buckets {
+		fmt.Fprint(w, bucket.Key)
+		for _, action := range actions {
+			fmt.Fprintf(w, "  %s: %d", action, len(bucket.DataLine[action]))
+			totals[action] += len(bucket.DataLine[action])
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "total  buckets: %d", len(buckets))
+	for _, action := range actions {
+		fmt.Fprintf(w, "  %s: %d", action, totals[action])
+	}
+	fmt.Fprintln(w)
 }
 
-func printContent(data map[string]DataLine) {
-	// print content of map
-	for key, value := range data {
-		fmt.Println("Key: ", key)
-		fmt.Println("Created: ", value.Created)
-		fmt.Println("Deleted: ", value.Deleted)
+// writeBuckets writes the buckets in chronological order in the given format
+func writeBuckets(w io.Writer, format string, buckets []*logparser.Bucket) error {
+	writer, err := logparser.NewBucketWriter(format, w)
+	if err != nil {
+		return err
 	}
+
+	for _, bucket := range buckets {
+		if err := writer.WriteBucket(bucket); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
 }
 
-func writeJSON(data map[string]DataLine) {
-	// print to JSON file from logData
-	jsonData, err := json.Marshal(data)
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the CLI and returns the process exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("log-parser", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: log-parser [flags] [file|glob|- ...]\n\nReads app.log when no input is given. Gzip input is decompressed automatically.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	output := flags.String("o", "output.json", "output file, or - for stdout")
+	format := flags.String("format", "json", "output format: "+strings.Join(logparser.OutputFormats, ", "))
+	summary := flags.Bool("summary", false, "print per-bucket counts to stdout instead of writing the full output")
+	profileName := flags.String("profile", "auto", "input format: auto, asynclogger, example or jsonl")
+	pattern := flags.String("pattern", "", "custom regex with timestamp, action and id groups, or a named-field pattern like \"[{timestamp}] Book {action}: {id}\" (overrides -profile)")
+	bucketSpec := flags.String("bucket", "minute", "bucket size: second, minute, hour, day or a duration such as 5m")
+	tzName := flags.String("tz", "", "timezone to normalize timestamps to before bucketing (Local, UTC, Asia/Jakarta, +07:00); empty keeps each line's own offset in the bucket key")
+	assumeTZName := flags.String("assume-tz", "Local", "timezone of timestamps that carry no offset")
+	follow := flags.Bool("follow", false, "tail a single log file and emit each bucket once its window has elapsed, until interrupted")
+	lateness := flags.Duration("lateness", 5*time.Second, "with -follow, how long to wait past a bucket's end for out-of-order lines")
+	pollInterval := flags.Duration("poll", 500*time.Millisecond, "with -follow, how often to check the file for new lines")
+	allowActions := flags.String("actions", "", "comma-separated actions to aggregate, always present in the output (default: every action found)")
+	denyActions := flags.String("exclude-actions", "", "comma-separated actions to ignore")
+	workers := flags.Int("workers", runtime.NumCPU(), "number of goroutines parsing each large uncompressed file in parallel")
+	sorted := flags.Bool("sorted", false, "input is in timestamp order: write each bucket as soon as a later one starts instead of holding every bucket in memory")
+	lifecycle := flags.Bool("lifecycle", false, "replay events in timestamp order and report each ID's net state and anomalies instead of buckets (formats: "+strings.Join(logparser.LifecycleFormats, ", ")+")")
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	bucketer, times, err := newTimeHandling(*bucketSpec, *tzName, *assumeTZName)
 	if err != nil {
-		fmt.Printf(err.Error())
-		return
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	if *lifecycle {
+		err = logparser.WriteLifecycle(io.Discard, *format, &logparser.LifecycleReport{})
+	} else {
+		_, err = logparser.NewBucketWriter(*format, io.Discard)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	if *lifecycle && *follow {
+		fmt.Fprintln(stderr, "-lifecycle cannot be combined with -follow")
+		return exitUsage
+	}
+	if *sorted && (*follow || *lifecycle || *summary) {
+		fmt.Fprintln(stderr, "-sorted cannot be combined with -follow, -lifecycle or -summary")
+		return exitUsage
 	}
 
-	// write to file
-	f, err := os.Create("output.json")
+	inputs := flags.Args()
+	if len(inputs) == 0 {
+		inputs = []string{"app.log"}
+	}
+	names, err := logparser.ExpandInputs(inputs)
 	if err != nil {
-		fmt.Printf(err.Error())
-		return
+		fmt.Fprintln(stderr, err)
+		return exitError
 	}
 
-	_, err = f.Write(jsonData)
+	filter := logparser.NewActionFilter(*allowActions, *denyActions)
+	newCollector := func() *collector {
+		c := &collector{times: times, aggregator: logparser.NewAggregator(bucketer, filter)}
+		if *lifecycle {
+			c.replayer = logparser.NewReplayer(bucketer)
+		}
+		return c
+	}
+	events := newCollector()
+	malformed := 0
+
+	if *follow {
+		if len(names) != 1 || names[0] == logparser.StdinName {
+			fmt.Fprintln(stderr, "-follow needs exactly one log file")
+			return exitUsage
+		}
+		if err := runFollow(names[0], *output, *format, *profileName, *pattern, times, events.aggregator, *lateness, *pollInterval, stdout, stderr); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	parseAll := func() error {
+		for _, name := range names {
+			report := func(err *logparser.ParseError) {
+				malformed++
+				fmt.Fprintf(stderr, "%s: %v\n", name, err)
+			}
+
+			unreported, err := parseInput(name, stdin, *profileName, *pattern, *workers, events, newCollector, report)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			if unreported > 0 {
+				malformed += unreported
+				fmt.Fprintf(stderr, "%s: %d more malformed line(s) not shown\n", name, unreported)
+			}
+		}
+		if malformed > 0 {
+			fmt.Fprintf(stderr, "skipped %d malformed line(s)\n", malformed)
+		}
+		return nil
+	}
+
+	if *sorted {
+		// Parse while writing so buckets leave memory as soon as they close;
+		// chunks parsed in parallel would not arrive in order
+		*workers = 1
+		err := writeOutput(*output, stdout, func(w io.Writer) error {
+			writer, err := logparser.NewBucketWriter(*format, w)
+			if err != nil {
+				return err
+			}
+			events.emitter = &logparser.SortedEmitter{Aggregator: events.aggregator, Writer: writer}
+			if err := parseAll(); err != nil {
+				return err
+			}
+			return events.emitter.Close()
+		})
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	if err := parseAll(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	if *lifecycle {
+		report := events.replayer.Report()
+		if *summary {
+			printLifecycle(stdout, report)
+			return exitOK
+		}
+		if err := writeOutput(*output, stdout, func(w io.Writer) error { return logparser.WriteLifecycle(w, *format, report) }); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	buckets := events.aggregator.Buckets()
+
+	if *summary {
+		printContent(stdout, buckets, events.aggregator.Actions())
+		return exitOK
+	}
+
+	if err := writeOutput(*output, stdout, func(w io.Writer) error { return writeBuckets(w, *format, buckets) }); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+// collector accumulates parsed events into buckets, into a lifecycle replay
+// when replayer is set, or writes buckets as they close when emitter is set
+type collector struct {
+	times      *logparser.TimeParser
+	aggregator *logparser.Aggregator
+	replayer   *logparser.Replayer
+	emitter    *logparser.SortedEmitter
+}
+
+// Add parses the event's timestamp and records it
+func (c *collector) Add(event logparser.Event) error {
+	eventTime, err := c.times.Parse(event.Timestamp)
 	if err != nil {
-		fmt.Printf(err.Error())
+		return err
+	}
+	event.Time = eventTime
+	if c.replayer != nil {
+		return c.replayer.Add(event)
+	}
+	if c.emitter != nil {
+		return c.emitter.Add(event)
+	}
+	return c.aggregator.Add(event)
+}
+
+// Merge adds the events of other, which should come later in the input
+func (c *collector) Merge(other *collector) {
+	if c.replayer != nil {
+		c.replayer.Merge(other.replayer)
 		return
 	}
+	c.aggregator.Merge(other.aggregator)
 }
 
-func main() {
+// parseInput opens a single input and scans it with the selected parser.
+// Large uncompressed files are split across workers, each collecting into its
+// own partial collector that is merged into events in input order. The
+// number of parse errors counted but not reported is returned.
+func parseInput(name string, stdin io.Reader, profileName, pattern string, workers int, events *collector, newCollector func() *collector, report func(*logparser.ParseError)) (int, error) {
+	if name != logparser.StdinName && workers > 1 {
+		parallel, unreported, err := parseFileParallel(name, profileName, pattern, workers, events, newCollector, report)
+		if parallel || err != nil {
+			return unreported, err
+		}
+	}
 
-	lineLength := 83
+	f, err := logparser.OpenInput(name, stdin)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
 
-	f, err := os.Open("app.log")
+	input, parser, err := logparser.SelectParser(f, profileName, pattern)
 	if err != nil {
-		fmt.Printf(err.Error())
-		return
+		return 0, err
+	}
+
+	return 0, logparser.Scan(input, parser, events.Add, report)
+}
+
+// parseFileParallel scans a regular, uncompressed file of at least
+// logparser.MinParallelSize with logparser.ScanParallel. It reports false,
+// without reading any events, for inputs that must be scanned sequentially,
+// and the number of parse errors ScanParallel did not report.
+func parseFileParallel(name, profileName, pattern string, workers int, events *collector, newCollector func() *collector, report func(*logparser.ParseError)) (bool, int, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return false, 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return false, 0, err
+	}
+	magic := make([]byte, 2)
+	if !info.Mode().IsRegular() || info.Size() < logparser.MinParallelSize {
+		return false, 0, nil
+	}
+	if _, err := f.ReadAt(magic, 0); err != nil || (magic[0] == 0x1f && magic[1] == 0x8b) {
+		return false, 0, nil
+	}
+
+	_, parser, err := logparser.SelectParser(io.NewSectionReader(f, 0, info.Size()), profileName, pattern)
+	if err != nil {
+		return true, 0, err
+	}
+
+	partials := make([]*collector, workers)
+	for i := range partials {
+		partials[i] = newCollector()
+	}
+	chunks, unreported, err := logparser.ScanParallel(f, info.Size(), workers, parser, func(chunk int, event logparser.Event) error {
+		return partials[chunk].Add(event)
+	}, report)
+	if err != nil {
+		return true, unreported, err
 	}
 
-	byteData := make([]byte, lineLength)
+	for _, partial := range partials[:chunks] {
+		events.Merge(partial)
+	}
+	return true, unreported, nil
+}
+
+// runFollow tails path and streams closed buckets to the output until SIGINT
+// or SIGTERM, then flushes the buckets still open
+func runFollow(path, output, format, profileName, pattern string, times *logparser.TimeParser, aggregator *logparser.Aggregator, lateness, interval time.Duration, stdout, stderr io.Writer) error {
+	tailer, err := logparser.NewTailer(path)
+	if err != nil {
+		return err
+	}
+	defer tailer.Close()
+
+	follower := &logparser.Follower{
+		Tailer:     tailer,
+		Times:      times,
+		Aggregator: aggregator,
+		Lateness:   lateness,
+		Interval:   interval,
+		Report: func(err *logparser.ParseError) {
+			fmt.Fprintf(stderr, "%s: %v\n", path, err)
+		},
+	}
 
-	// declare array of string
-	var logData map[string]DataLine = make(map[string]DataLine)
-	var lines []string
+	switch {
+	case pattern != "":
+		follower.Parser, err = logparser.NewPatternParser(pattern)
+	case profileName == "auto":
+		follower.Detect = true
+	default:
+		var profile logparser.Profile
+		if profile, err = logparser.ProfileByName(profileName); err == nil {
+			follower.Parser, err = profile.NewParser()
+		}
+	}
+	if err != nil {
+		return err
+	}
 
-	// read line by line
-	for {
-		_, err := f.Read(byteData)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return writeOutput(output, stdout, func(w io.Writer) error {
+		writer, err := logparser.NewBucketWriter(format, w)
 		if err != nil {
-			fmt.Printf(err.Error())
-			break
+			return err
 		}
+		follower.Writer = writer
+		return follower.Run(ctx)
+	})
+}
 
-		// append line to array
-		lines = append(lines, string(byteData))
+// writeOutput writes to the named file, or to stdout for "-"
+func writeOutput(path string, stdout io.Writer, write func(io.Writer) error) error {
+	if path == logparser.StdinName {
+		return write(stdout)
+	}
 
-		// fetch timestamp, action keyword, and UUID from the line
-		timestamp := string(byteData[1:17])
-		action := string(byteData[37:44])
-		uuid := string(byteData[46:82])
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
 
-		// insert timestamp if not exist in map
-		if _, ok := logData[timestamp]; !ok {
-			logData[timestamp] = DataLine{Created: []string{}, Deleted: []string{}}
+// newTimeHandling builds the bucketer and timestamp parser from the flags
+func newTimeHandling(bucketSpec, tzName, assumeTZName string) (*logparser.Bucketer, *logparser.TimeParser, error) {
+	var tz *time.Location
+	if tzName != "" {
+		loc, err := logparser.LoadLocation(tzName)
+		if err != nil {
+			return nil, nil, err
 		}
+		tz = loc
+	}
 
-		if action == "created" {
-			tempCreated := logData[timestamp].Created
-			tempCreated = append(tempCreated, uuid)
-			logData[timestamp] = DataLine{Created: tempCreated, Deleted: logData[timestamp].Deleted}
-		} else if action == "deleted" {
-			tempDeleted := logData[timestamp].Created
-			tempDeleted = append(tempDeleted, uuid)
-			logData[timestamp] = DataLine{Created: logData[timestamp].Created, Deleted: tempDeleted}
-		} else {
-			// fmt.Println("Invalid action")
-			return
-		}
+	assume, err := logparser.LoadLocation(assumeTZName)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	writeJSON(logData)
+	bucketer, err := logparser.NewBucketer(bucketSpec, tz)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bucketer, logparser.NewTimeParser(assume), nil
+}
 
-	f.Close()
+// printLifecycle prints the net state counts and each anomaly
+func printLifecycle(w io.Writer, report *logparser.LifecycleReport) {
+	states := make(map[string]int)
+	for _, book := range report.Books {
+		states[book.State]++
+	}
+	fmt.Fprintf(w, "ids: %d  alive: %d  deleted: %d  unknown: %d  anomalies: %d\n",
+		len(report.Books), states[logparser.StateAlive], states[logparser.StateDeleted], states[logparser.StateUnknown], len(report.Anomalies))
+
+	for _, anomaly := range report.Anomalies {
+		fmt.Fprintf(w, "%s  %s  %s\n", anomaly.Time.Format(time.RFC3339Nano), anomaly.Kind, anomaly.ID)
+	}
 }
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunMatchesOutputExample(t *testing.T) {
+	want, err := os.ReadFile("output_example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The example is in bucket order, so -sorted writes the same output
+	for _, args := range [][]string{{"app.log.example"}, {"-sorted", "app.log.example"}} {
+		output := filepath.Join(t.TempDir(), "output.json")
+		var stderr bytes.Buffer
+		if code := run(append([]string{"-o", output}, args...), nil, &bytes.Buffer{}, &stderr); code != exitOK {
+			t.Fatalf("run(%v) = %d, stderr: %s", args, code, stderr.String())
+		}
+
+		got, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("run(%v) output differs from output_example.json:\n%s", args, got)
+		}
+	}
+}
+
+func TestRunExitCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "unknown flag", args: []string{"-nope"}, want: exitUsage},
+		{name: "unknown format", args: []string{"-format", "xml", "app.log.example"}, want: exitUsage},
+		{name: "missing input", args: []string{"-o", "-", "does-not-exist.log"}, want: exitError},
+		{name: "summary", args: []string{"-summary", "app.log.example"}, want: exitOK},
+		{name: "sorted with summary", args: []string{"-sorted", "-summary", "app.log.example"}, want: exitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := run(tt.args, nil, &bytes.Buffer{}, &bytes.Buffer{}); got != tt.want {
+				t.Errorf("run() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}